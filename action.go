@@ -1,40 +1,373 @@
 package gin
 
 import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
 	contractshttp "github.com/goravel/framework/contracts/http"
 	contractsroute "github.com/goravel/framework/contracts/route"
 )
 
+const routeActionKey = "goravel_routeAction"
+
+const (
+	patternAlpha        = "[a-zA-Z]+"
+	patternAlphaNumeric = "[a-zA-Z0-9]+"
+	patternNumber       = "[0-9]+"
+	patternUuid         = "[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}"
+)
+
 type Action struct {
-	method string
-	path   string
+	registry   *RouteRegistry
+	method     string
+	path       string
+	namePrefix string
+	// the fields below are only set for the routes registered by a Group, they are
+	// registered to gin when the Route starts serving, so they can still be modified until then, the modifiers
+	// panic afterwards.
+	group              *Group
+	routes             []actionRoute
+	wheres             map[string]*regexp.Regexp
+	defaults           map[string]string
+	middlewares        []contractshttp.Middleware
+	aliases            []string
+	withoutMiddlewares []contractshttp.Middleware
+	withoutAliases     []string
+	timeout            *time.Duration
+	bodyLimit          *int64
+	resource           *actionResource
+	registered         atomic.Bool
 }
 
-func NewAction(method, path, handler string) contractsroute.Action {
-	if _, ok := routes[path]; !ok {
-		routes[path] = make(map[string]contractshttp.Info)
-	}
+// actionRoute is a route registered to gin, infoPath and infoMethod locate its information in the registry.
+type actionRoute struct {
+	action     *Action
+	method     string
+	path       string
+	handler    contractshttp.HandlerFunc
+	infoPath   string
+	infoMethod string
+	// whether a Timeout middleware applies to the route, it's served by serveWithTimeout then
+	timeout bool
+}
+
+// Info returns the route information of the matched route.
+func (r *actionRoute) Info() (contractshttp.Info, bool) {
+	return r.action.registry.Get(r.infoPath, r.infoMethod)
+}
 
+func NewAction(registry *RouteRegistry, method, path, handler string) contractsroute.Action {
 	if method == contractshttp.MethodGet {
 		method = contractshttp.MethodGet + "|" + contractshttp.MethodHead
 	}
 
-	routes[path][method] = contractshttp.Info{
+	registry.Add(contractshttp.Info{
 		Handler: handler,
 		Method:  method,
 		Path:    path,
-	}
+	})
 
 	return &Action{
-		method: method,
-		path:   path,
+		registry: registry,
+		method:   method,
+		path:     path,
+	}
+}
+
+func newGroupAction(group *Group, method, path, handler string, routes ...actionRoute) *Action {
+	if err := checkOptionalPath(path); err != nil {
+		panic(err)
+	}
+
+	action := NewAction(group.registry, method, group.getDomainPath(path), handler).(*Action)
+	action.group = group
+	action.namePrefix = group.name
+	action.wheres = make(map[string]*regexp.Regexp)
+	action.defaults = make(map[string]string)
+	action.timeout = group.timeout
+	action.bodyLimit = group.bodyLimit
+	for _, route := range routes {
+		route.action = action
+		route.infoPath = action.path
+		route.infoMethod = action.method
+		action.routes = append(action.routes, route)
 	}
+	group.registry.addAction(action.path, action.method, action)
+	group.registry.addPending(action)
+
+	return action
 }
 
+// Name sets the name of the route. For a resource, it names the resource and prefixes the names of the
+// generated routes, e.g. Name("photos") names the index route photos.index.
 func (r *Action) Name(name string) contractsroute.Action {
-	info := routes[r.path][r.method]
-	info.Name = name
-	routes[r.path][r.method] = info
+	if r.resource != nil {
+		r.resource.name = name
+		r.syncResource()
+
+		return r
+	}
+
+	r.registry.SetName(r.path, r.method, r.namePrefix+name)
+
+	return r
+}
+
+// Info returns the route information of the action.
+func (r *Action) Info() (contractshttp.Info, bool) {
+	return r.registry.Get(r.path, r.method)
+}
+
+// Default sets the value of an optional route parameter, e.g. {page?}, when it's absent in the request. The optional
+// parameters must be trailing, registering a route like /posts/{slug?}/comments panics.
+func (r *Action) Default(key, value string) *Action {
+	r.checkRegistered()
+	if r.defaults != nil {
+		r.defaults[key] = value
+	}
+
+	return r
+}
+
+// Middleware appends middlewares to the route, they run after the middlewares of the group.
+func (r *Action) Middleware(middlewares ...contractshttp.Middleware) *Action {
+	r.checkRegistered()
+	if r.group != nil {
+		r.middlewares = append(r.middlewares, middlewares...)
+		r.aliases = append(r.aliases, make([]string, len(middlewares))...)
+	}
+
+	return r
+}
+
+// MiddlewareByName appends the middlewares resolved from the aliases or middleware groups, see Group.MiddlewareByName.
+func (r *Action) MiddlewareByName(names ...string) *Action {
+	r.checkRegistered()
+	if r.group != nil {
+		middlewares, aliases := r.registry.mustResolveMiddlewares(names)
+		r.middlewares = append(r.middlewares, middlewares...)
+		r.aliases = append(r.aliases, aliases...)
+	}
+
+	return r
+}
+
+// WithoutMiddleware excludes the middlewares inherited from the group. Middlewares are compared by identity, so
+// pass the same values that are given to the group, the closures created by separate calls, e.g. Throttle("login")
+// and Throttle("api"), are different middlewares.
+func (r *Action) WithoutMiddleware(middlewares ...contractshttp.Middleware) *Action {
+	r.checkRegistered()
+	if r.group != nil {
+		r.withoutMiddlewares = append(r.withoutMiddlewares, middlewares...)
+	}
+
+	return r
+}
+
+// WithoutMiddlewareByName excludes the middlewares inherited from the group that are resolved from the aliases,
+// the middleware groups are expanded to their aliases, e.g. WithoutMiddlewareByName("throttle") excludes both
+// "throttle:login" and "throttle:api". It panics if a middleware is not defined.
+func (r *Action) WithoutMiddlewareByName(names ...string) *Action {
+	r.checkRegistered()
+	if r.group != nil {
+		_, aliases := r.registry.mustResolveMiddlewares(names)
+		r.withoutAliases = append(r.withoutAliases, aliases...)
+	}
+
+	return r
+}
+
+// Timeout sets the timeout of the route, it overrides http.request_timeout and the timeout of the group,
+// Timeout(0) disables it.
+func (r *Action) Timeout(timeout time.Duration) *Action {
+	r.checkRegistered()
+	if r.group != nil {
+		r.timeout = &timeout
+	}
+
+	return r
+}
+
+// BodyLimit sets the max size of the request body in bytes, it overrides http.drivers.ginx.body_limit and
+// the body limit of the group.
+func (r *Action) BodyLimit(bytes int64) *Action {
+	r.checkRegistered()
+	if r.group != nil {
+		r.bodyLimit = &bytes
+	}
 
 	return r
 }
+
+// Where constrains the route parameter with a regular expression, requests whose parameter
+// doesn't match the pattern are handled as not found.
+func (r *Action) Where(key, pattern string) *Action {
+	r.checkRegistered()
+	if r.wheres != nil {
+		r.wheres[key] = compilePattern(pattern)
+	}
+
+	return r
+}
+
+func (r *Action) WhereAlpha(keys ...string) *Action {
+	return r.whereKeys(keys, patternAlpha)
+}
+
+func (r *Action) WhereAlphaNumeric(keys ...string) *Action {
+	return r.whereKeys(keys, patternAlphaNumeric)
+}
+
+func (r *Action) WhereIn(key string, values []string) *Action {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = regexp.QuoteMeta(value)
+	}
+
+	return r.Where(key, strings.Join(quoted, "|"))
+}
+
+func (r *Action) WhereNumber(keys ...string) *Action {
+	return r.whereKeys(keys, patternNumber)
+}
+
+func (r *Action) WhereUuid(keys ...string) *Action {
+	return r.whereKeys(keys, patternUuid)
+}
+
+// checkRegistered panics if the routes of the action are registered to gin, they can't be modified afterwards.
+func (r *Action) checkRegistered() {
+	if r.registered.Load() {
+		panic(fmt.Errorf("the route %s %s is already registered, it can't be modified", r.method, r.path))
+	}
+}
+
+func (r *Action) whereKeys(keys []string, pattern string) *Action {
+	for _, key := range keys {
+		r.Where(key, pattern)
+	}
+
+	return r
+}
+
+// register registers the routes of the action to gin, the panics of gin, e.g. conflicting routes, are returned
+// as errors.
+func (r *Action) register() (err error) {
+	r.registered.Store(true)
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("failed to register the route %s %s: %v", r.method, r.path, recovered)
+		}
+	}()
+
+	wheres := r.registry.Patterns()
+	for key, pattern := range r.wheres {
+		wheres[key] = pattern
+	}
+
+	routeMiddlewares, aliases := r.middlewaresWithAliases()
+	middlewares := middlewaresToGinHandlers(r.registry.sortMiddlewares(routeMiddlewares, aliases))
+	lastMiddlewares := middlewaresToGinHandlers(r.group.lastMiddlewares)
+	timeout := r.timeout != nil && *r.timeout > 0 || slices.ContainsFunc(routeMiddlewares, isTimeoutMiddleware)
+
+	for i := range r.routes {
+		route := &r.routes[i]
+		route.timeout = timeout
+		handlers := []gin.HandlerFunc{r.toGinHandler(route, routeConstraints(r.group.domain+route.path, wheres))}
+		if r.timeout != nil && *r.timeout > 0 {
+			handlers = append(handlers, middlewareToGinHandler(Timeout(*r.timeout)))
+		}
+		handlers = append(handlers, middlewares...)
+		// the models are resolved after the middlewares, so they are not resolved for unauthorized requests.
+		if keys, resolvers := r.registry.modelResolvers(r.group.domain + route.path); len(keys) > 0 {
+			handlers = append(handlers, middlewareToGinHandler(bindModels(r.registry, keys, resolvers)))
+		}
+		handlers = append(handlers, lastMiddlewares...)
+		handlers = append(handlers, handlerToGinHandler(route.handler))
+
+		for _, path := range expandOptionalPath(route.path) {
+			ginPath := pathToGinPath(path)
+			if r.group.domain != "" {
+				ginPath = r.registry.addDomainPath(r.group.domain, ginPath, false)
+			}
+			r.registry.addGinRoute(route.method, ginPath, route)
+			if route.method == contractshttp.MethodAny {
+				r.group.instance.Any(ginPath, handlers...)
+			} else {
+				r.group.instance.Handle(route.method, ginPath, handlers...)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Middlewares returns the middlewares of the route, including the ones inherited from the group.
+func (r *Action) Middlewares() []contractshttp.Middleware {
+	middlewares, _ := r.middlewaresWithAliases()
+
+	return middlewares
+}
+
+func (r *Action) middlewaresWithAliases() ([]contractshttp.Middleware, []string) {
+	if r.group == nil {
+		return nil, nil
+	}
+
+	var (
+		middlewares []contractshttp.Middleware
+		aliases     []string
+	)
+	groupAliases := r.group.getAliases()
+	for i, middleware := range r.group.middlewares {
+		if !containsMiddleware(r.withoutMiddlewares, middleware) && !slices.Contains(r.withoutAliases, groupAliases[i]) {
+			middlewares = append(middlewares, middleware)
+			aliases = append(aliases, groupAliases[i])
+		}
+	}
+
+	return append(middlewares, r.middlewares...), append(aliases, r.aliases...)
+}
+
+// toGinHandler creates the first handler of the route, it checks the parameter constraints and fills
+// the default values of the absent optional parameters.
+func (r *Action) toGinHandler(route *actionRoute, constraints map[string]*regexp.Regexp) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(routeActionKey, route)
+
+		for key, pattern := range constraints {
+			if value, exist := c.Params.Get(key); exist && !pattern.MatchString(value) {
+				r.registry.notFound(c)
+				return
+			}
+		}
+
+		for key, value := range r.defaults {
+			if _, exist := c.Params.Get(key); !exist {
+				c.Params = append(c.Params, gin.Param{Key: key, Value: value})
+			}
+		}
+	}
+}
+
+// routeConstraints returns the patterns of the parameters that exist in the path.
+func routeConstraints(path string, wheres map[string]*regexp.Regexp) map[string]*regexp.Regexp {
+	constraints := make(map[string]*regexp.Regexp)
+	for _, match := range routeParameterRegex.FindAllStringSubmatch(path, -1) {
+		key := strings.TrimSuffix(match[1], "?")
+		if pattern, exist := wheres[key]; exist {
+			constraints[key] = pattern
+		}
+	}
+
+	return constraints
+}
+
+func compilePattern(pattern string) *regexp.Regexp {
+	return regexp.MustCompile("^(?:" + pattern + ")$")
+}
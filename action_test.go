@@ -3,21 +3,19 @@ package gin
 import (
 	"testing"
 
-	contractshttp "github.com/goravel/framework/contracts/http"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestNewAction(t *testing.T) {
-	// Clear routes map before test
-	routes = make(map[string]map[string]contractshttp.Info)
+	registry := NewRouteRegistry()
 
 	// Test creating a new action
-	action := NewAction("GET", "/test-path", "test.Action")
+	action := NewAction(registry, "GET", "/test-path", "test.Action")
 	assert.NotNil(t, action)
 	assert.IsType(t, &Action{}, action)
 
-	// Verify route was added to routes map
-	routeInfo, exists := routes["/test-path"]["GET|HEAD"]
+	// Verify route was added to the registry
+	routeInfo, exists := registry.Get("/test-path", "GET|HEAD")
 	assert.True(t, exists)
 	assert.Equal(t, "GET|HEAD", routeInfo.Method)
 	assert.Equal(t, "/test-path", routeInfo.Path)
@@ -26,11 +24,10 @@ func TestNewAction(t *testing.T) {
 }
 
 func TestAction_Name(t *testing.T) {
-	// Clear routes map before test
-	routes = make(map[string]map[string]contractshttp.Info)
+	registry := NewRouteRegistry()
 
 	// Create a new action
-	action := NewAction("GET", "/named-path", "")
+	action := NewAction(registry, "GET", "/named-path", "")
 
 	// Test setting name
 	namedAction := action.Name("test-route")
@@ -38,7 +35,7 @@ func TestAction_Name(t *testing.T) {
 	assert.IsType(t, &Action{}, namedAction)
 
 	// Verify route info was updated
-	routeInfo, exists := routes["/named-path"]["GET|HEAD"]
+	routeInfo, exists := registry.Get("/named-path", "GET|HEAD")
 	assert.True(t, exists)
 	assert.Equal(t, "GET|HEAD", routeInfo.Method)
 	assert.Equal(t, "/named-path", routeInfo.Path)
@@ -49,7 +46,23 @@ func TestAction_Name(t *testing.T) {
 	assert.NotNil(t, chainedAction)
 
 	// Verify final route info
-	routeInfo, exists = routes["/named-path"]["GET|HEAD"]
+	routeInfo, exists = registry.Get("/named-path", "GET|HEAD")
 	assert.True(t, exists)
 	assert.Equal(t, "final-name", routeInfo.Name)
 }
+
+func TestAction_RegistryIsolation(t *testing.T) {
+	registryA := NewRouteRegistry()
+	registryB := NewRouteRegistry()
+
+	NewAction(registryA, "GET", "/shared", "a.Action").Name("a")
+	NewAction(registryB, "POST", "/shared", "b.Action").Name("b")
+
+	assert.Len(t, registryA.All(), 1)
+	assert.Len(t, registryB.All(), 1)
+
+	_, exists := registryA.Named("b")
+	assert.False(t, exists)
+	_, exists = registryB.Named("a")
+	assert.False(t, exists)
+}
@@ -0,0 +1,187 @@
+package gin
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// CertificateLoader serves the TLS certificates by tls.Config.GetCertificate, so they can be rotated without
+// restarting the server. The certificates are reloaded when their files change or the process receives SIGHUP,
+// the last good certificates are kept when reloading fails.
+type CertificateLoader struct {
+	mu       sync.Mutex
+	files    []certificateFiles
+	current  atomic.Pointer[certificateSet]
+	interval time.Duration
+}
+
+type certificateFiles struct {
+	certFile string
+	keyFile  string
+	// the modification time and the size of the files when they are loaded
+	stamp string
+}
+
+// certificateSet is the loaded certificates, the first one is served when no name matches the SNI.
+type certificateSet struct {
+	certificates []*tls.Certificate
+	names        map[string]*tls.Certificate
+}
+
+// NewCertificateLoader creates a loader that serves the certificate of certFile and keyFile by default.
+func NewCertificateLoader(certFile, keyFile string) (*CertificateLoader, error) {
+	loader := &CertificateLoader{interval: 5 * time.Second}
+	if err := loader.Add(certFile, keyFile); err != nil {
+		return nil, err
+	}
+
+	return loader, nil
+}
+
+// Add adds a certificate that's served when the SNI of the client matches its DNS names.
+func (r *CertificateLoader) Add(certFile, keyFile string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.files = append(r.files, certificateFiles{certFile: certFile, keyFile: keyFile})
+	if err := r.load(); err != nil {
+		r.files = r.files[:len(r.files)-1]
+
+		return err
+	}
+
+	return nil
+}
+
+// Interval sets how often the files are checked for changes, it's 5 seconds by default.
+func (r *CertificateLoader) Interval(interval time.Duration) *CertificateLoader {
+	r.interval = interval
+
+	return r
+}
+
+// GetCertificate returns the certificate whose DNS names match the SNI of the client, the wildcard names match
+// one label, and the default certificate is returned if none matches.
+func (r *CertificateLoader) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	set := r.current.Load()
+
+	name := strings.TrimSuffix(strings.ToLower(hello.ServerName), ".")
+	if certificate, exist := set.names[name]; exist {
+		return certificate, nil
+	}
+	if index := strings.Index(name, "."); index > 0 {
+		if certificate, exist := set.names["*"+name[index:]]; exist {
+			return certificate, nil
+		}
+	}
+
+	return set.certificates[0], nil
+}
+
+// Reload loads all the certificates again, they are replaced only if all of them are loaded.
+func (r *CertificateLoader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.load(); err != nil {
+		if LogFacade != nil {
+			LogFacade.Error(err)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// Watch reloads the certificates when their files change or the process receives SIGHUP until ctx is done.
+func (r *CertificateLoader) Watch(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			_ = r.Reload()
+		case <-ticker.C:
+			if r.changed() {
+				_ = r.Reload()
+			}
+		}
+	}
+}
+
+func (r *CertificateLoader) changed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, files := range r.files {
+		if files.stamp != certificateStamp(files.certFile, files.keyFile) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *CertificateLoader) load() error {
+	set := &certificateSet{names: make(map[string]*tls.Certificate)}
+	for i := range r.files {
+		files := &r.files[i]
+		// the stamp is updated even if loading fails, so the broken files aren't loaded again until they change
+		files.stamp = certificateStamp(files.certFile, files.keyFile)
+
+		certificate, err := tls.LoadX509KeyPair(files.certFile, files.keyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load the certificate %s: %w", files.certFile, err)
+		}
+
+		set.certificates = append(set.certificates, &certificate)
+		if certificate.Leaf == nil {
+			continue
+		}
+
+		names := certificate.Leaf.DNSNames
+		if len(names) == 0 && certificate.Leaf.Subject.CommonName != "" {
+			names = []string{certificate.Leaf.Subject.CommonName}
+		}
+		for _, name := range names {
+			name = strings.ToLower(name)
+			// the earlier certificates take precedence
+			if _, exist := set.names[name]; !exist {
+				set.names[name] = &certificate
+			}
+		}
+	}
+
+	r.current.Store(set)
+
+	return nil
+}
+
+func certificateStamp(files ...string) string {
+	var stamp strings.Builder
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			stamp.WriteString(fmt.Sprintf("%d-%d;", info.ModTime().UnixNano(), info.Size()))
+		} else {
+			stamp.WriteString("-;")
+		}
+	}
+
+	return stamp.String()
+}
@@ -0,0 +1,192 @@
+package gin
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+	configmocks "github.com/goravel/framework/mocks/config"
+	mockslog "github.com/goravel/framework/mocks/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertificateLoader(t *testing.T) {
+	dir := t.TempDir()
+	defaultCert, defaultKey := writeCertificate(t, dir, "default", 1, "default.example.com")
+	sniCert, sniKey := writeCertificate(t, dir, "sni", 2, "*.goravel.dev")
+
+	loader, err := NewCertificateLoader(defaultCert, defaultKey)
+	require.NoError(t, err)
+	require.NoError(t, loader.Add(sniCert, sniKey))
+	assert.ErrorContains(t, loader.Add(filepath.Join(dir, "missing.crt"), sniKey), "failed to load the certificate")
+
+	serial := func(serverName string) int64 {
+		certificate, err := loader.GetCertificate(&tls.ClientHelloInfo{ServerName: serverName})
+		require.NoError(t, err)
+
+		return certificate.Leaf.SerialNumber.Int64()
+	}
+
+	assert.Equal(t, int64(1), serial("default.example.com"))
+	assert.Equal(t, int64(2), serial("api.goravel.dev"))
+	assert.Equal(t, int64(2), serial("API.Goravel.dev"))
+	assert.Equal(t, int64(1), serial("a.b.goravel.dev"))
+	assert.Equal(t, int64(1), serial(""))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watching := make(chan struct{})
+	go func() {
+		defer close(watching)
+		loader.Interval(10 * time.Millisecond).Watch(ctx)
+	}()
+
+	// wait for the watcher to start, so the changes below are found
+	time.Sleep(50 * time.Millisecond)
+
+	t.Run("reload when the files change", func(t *testing.T) {
+		writeCertificate(t, dir, "sni", 3, "*.goravel.dev")
+
+		assert.Eventually(t, func() bool {
+			return serial("api.goravel.dev") == 3
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("keep the last good certificates", func(t *testing.T) {
+		// stop watching, so the broken files are only loaded below
+		cancel()
+		<-watching
+
+		mockLog := mockslog.NewLog(t)
+		mockLog.EXPECT().Error(mock.Anything).Return().Once()
+		LogFacade = mockLog
+		defer func() {
+			LogFacade = nil
+		}()
+
+		require.NoError(t, os.WriteFile(defaultCert, []byte("broken"), 0644))
+
+		assert.ErrorContains(t, loader.Reload(), "failed to load the certificate "+defaultCert)
+		assert.Equal(t, int64(1), serial("default.example.com"))
+		assert.Equal(t, int64(3), serial("api.goravel.dev"))
+	})
+
+	t.Run("reload on SIGHUP", func(t *testing.T) {
+		writeCertificate(t, dir, "default", 4, "default.example.com")
+
+		// the files aren't checked in the test, so they are only reloaded by the signal
+		loader, err := NewCertificateLoader(defaultCert, defaultKey)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go loader.Interval(time.Hour).Watch(ctx)
+		time.Sleep(50 * time.Millisecond)
+
+		writeCertificate(t, dir, "default", 5, "default.example.com")
+		process, err := os.FindProcess(os.Getpid())
+		require.NoError(t, err)
+		require.NoError(t, process.Signal(syscall.SIGHUP))
+
+		assert.Eventually(t, func() bool {
+			certificate, err := loader.GetCertificate(&tls.ClientHelloInfo{})
+			require.NoError(t, err)
+
+			return certificate.Leaf.SerialNumber.Int64() == 5
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestRoute_Certificate(t *testing.T) {
+	dir := t.TempDir()
+	defaultCert, defaultKey := writeCertificate(t, dir, "default", 1, "default.example.com")
+	sniCert, sniKey := writeCertificate(t, dir, "sni", 2, "api.goravel.dev")
+
+	mockConfig := configmocks.NewConfig(t)
+	mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.auto_options", true).Return(true).Once()
+
+	route, err := NewRoute(mockConfig, nil)
+	require.NoError(t, err)
+
+	route.Get("/", func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().Success().String("Goravel")
+	})
+	route.Certificate(sniCert, sniKey)
+
+	mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+	mockTLSServerConfig(mockConfig)
+
+	l, err := net.Listen("tcp", "127.0.0.1:3045")
+	require.NoError(t, err)
+
+	done := make(chan error)
+	go func() {
+		done <- route.ListenTLSWithCert(l, defaultCert, defaultKey)
+	}()
+
+	for serverName, expectSerial := range map[string]int64{"api.goravel.dev": 2, "localhost": 1} {
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true, ServerName: serverName},
+		}}
+
+		assert.EventuallyWithT(t, func(t *assert.CollectT) {
+			resp, err := client.Get("https://127.0.0.1:3045")
+			if !assert.NoError(t, err) {
+				return
+			}
+			_ = resp.Body.Close()
+
+			assert.Equal(t, expectSerial, resp.TLS.PeerCertificates[0].SerialNumber.Int64())
+		}, time.Second, 10*time.Millisecond, serverName)
+	}
+
+	assert.NoError(t, route.Shutdown())
+	assert.NoError(t, <-done)
+}
+
+// writeCertificate writes a self-signed certificate and its key to dir, the files are replaced if they exist.
+func writeCertificate(t *testing.T, dir, name string, serial int64, dnsNames ...string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644))
+
+	return certFile, keyFile
+}
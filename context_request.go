@@ -2,6 +2,7 @@ package gin
 
 import (
 	"bytes"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
@@ -44,7 +45,11 @@ func NewContextRequest(ctx *Context, log log.Log, validation contractsvalidate.V
 	request := contextRequestPool.Get().(*ContextRequest)
 	httpBody, err := getHttpBody(ctx)
 	if err != nil {
-		log.Error(fmt.Sprintf("%+v", err))
+		// the body larger than the body limit is responded by bodyLimitMiddleware when it's read
+		var maxBytesError *http.MaxBytesError
+		if !errors.As(err, &maxBytesError) {
+			log.Error(fmt.Sprintf("%+v", err))
+		}
 	}
 	request.ctx = ctx
 	request.instance = ctx.instance
@@ -104,6 +109,34 @@ func (r *ContextRequest) BindQuery(obj any) error {
 	return r.instance.ShouldBindQuery(obj)
 }
 
+// ClientCertificate returns the certificate of the client in mutual TLS, it's nil if the client doesn't send one.
+// The certificate is only verified when the http.drivers.ginx.client_auth config is require or verify_if_given.
+func (r *ContextRequest) ClientCertificate() *x509.Certificate {
+	if state := r.instance.Request.TLS; state != nil && len(state.PeerCertificates) > 0 {
+		return state.PeerCertificates[0]
+	}
+
+	return nil
+}
+
+// ClientIdentity returns the identity of the verified client certificate, it's the first URI SAN like a SPIFFE ID,
+// the first DNS SAN or the subject common name, and it's empty if no certificate is verified.
+func (r *ContextRequest) ClientIdentity() string {
+	certificate := verifiedClientCertificate(r.instance.Request)
+	if certificate == nil {
+		return ""
+	}
+
+	switch {
+	case len(certificate.URIs) > 0:
+		return certificate.URIs[0].String()
+	case len(certificate.DNSNames) > 0:
+		return certificate.DNSNames[0]
+	default:
+		return certificate.Subject.CommonName
+	}
+}
+
 func (r *ContextRequest) Cookie(key string, defaultValue ...string) string {
 	cookie, err := r.instance.Cookie(key)
 	if err != nil {
@@ -296,7 +329,7 @@ func (r *ContextRequest) Origin() *http.Request {
 }
 
 func (r *ContextRequest) OriginPath() string {
-	return colonToBracket(r.instance.FullPath())
+	return colonToBracket(trimDomainPrefix(r.instance.FullPath()))
 }
 
 func (r *ContextRequest) Path() string {
@@ -304,34 +337,33 @@ func (r *ContextRequest) Path() string {
 }
 
 func (r *ContextRequest) Info() contractshttp.Info {
-	methodToInfo, exist := routes[r.OriginPath()]
-	if !exist {
-		return contractshttp.Info{}
+	if route, exist := r.instance.Get(routeActionKey); exist {
+		if info, exist := route.(*actionRoute).Info(); exist {
+			info.Method = r.Method()
+			return info
+		}
 	}
 
-	method := r.Method()
-
-	methodsToTry := []string{
-		method,
-		contractshttp.MethodAny,
-		contractshttp.MethodResource,
+	registry := routeRegistryFromContext(r.instance)
+	if registry == nil {
+		return contractshttp.Info{}
 	}
 
-	if method == contractshttp.MethodGet || method == contractshttp.MethodHead {
-		methodsToTry = append([]string{contractshttp.MethodGet + "|" + contractshttp.MethodHead}, methodsToTry...)
-	}
+	info, exist := registry.Match(r.OriginPath(), r.Method())
+	if !exist {
+		if !registry.Has(r.OriginPath()) {
+			return contractshttp.Info{}
+		}
 
-	for _, tryMethod := range methodsToTry {
-		if info, exist := methodToInfo[tryMethod]; exist {
-			info.Method = r.Method()
-			return info
+		return contractshttp.Info{
+			Method: r.Method(),
+			Path:   r.OriginPath(),
 		}
 	}
 
-	return contractshttp.Info{
-		Method: r.Method(),
-		Path:   r.OriginPath(),
-	}
+	info.Method = r.Method()
+
+	return info
 }
 
 func (r *ContextRequest) Input(key string, defaultValue ...string) string {
@@ -620,7 +652,7 @@ func getHttpBody(ctx *Context) (map[string]any, error) {
 		bodyBytes, err := io.ReadAll(request.Body)
 		_ = request.Body.Close()
 		if err != nil {
-			return nil, fmt.Errorf("retrieve json error: %v", err)
+			return nil, fmt.Errorf("retrieve json error: %w", err)
 		}
 
 		if len(bodyBytes) > 0 {
@@ -636,7 +668,7 @@ func getHttpBody(ctx *Context) (map[string]any, error) {
 		if request.PostForm == nil {
 			const defaultMemory = 32 << 20
 			if err := request.ParseMultipartForm(defaultMemory); err != nil {
-				return nil, fmt.Errorf("parse multipart form error: %v", err)
+				return nil, fmt.Errorf("parse multipart form error: %w", err)
 			}
 		}
 		for k, v := range request.PostForm {
@@ -658,7 +690,7 @@ func getHttpBody(ctx *Context) (map[string]any, error) {
 	if contentType == "application/x-www-form-urlencoded" {
 		if request.PostForm == nil {
 			if err := request.ParseForm(); err != nil {
-				return nil, fmt.Errorf("parse form error: %v", err)
+				return nil, fmt.Errorf("parse form error: %w", err)
 			}
 		}
 		for k, v := range request.PostForm {
@@ -41,6 +41,8 @@ func (s *ContextRequestSuite) SetupTest() {
 	s.mockConfig = &mocksconfig.Config{}
 	s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
 	s.mockConfig.EXPECT().GetInt("http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.ginx.auto_options", true).Return(true).Once()
 	ValidationFacade = validation.NewValidation()
 
 	var err error
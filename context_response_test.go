@@ -27,6 +27,8 @@ func (s *ContextResponseSuite) SetupTest() {
 	s.mockConfig = &mocksconfig.Config{}
 	s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
 	s.mockConfig.EXPECT().GetInt("http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.ginx.auto_options", true).Return(true).Once()
 
 	var err error
 	s.route, err = NewRoute(s.mockConfig, nil)
@@ -147,6 +149,7 @@ func (s *ContextResponseSuite) TestOrigin() {
 	s.mockConfig.EXPECT().GetString("http.tls.ssl.cert").Return("").Once()
 	s.mockConfig.EXPECT().GetString("http.tls.ssl.key").Return("").Once()
 	s.mockConfig.EXPECT().GetInt("http.request_timeout", 3).Return(1).Once()
+	s.mockConfig.EXPECT().GetInt("http.drivers.ginx.max_in_flight", 0).Return(0).Once()
 	ConfigFacade = s.mockConfig
 
 	s.route.GlobalMiddleware(func(ctx contractshttp.Context) {
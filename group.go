@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"slices"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/goravel/framework/contracts/config"
@@ -14,17 +16,26 @@ import (
 )
 
 type Group struct {
-	config          config.Config
-	instance        gin.IRouter
-	prefix          string
-	middlewares     []contractshttp.Middleware
+	config      config.Config
+	instance    gin.IRouter
+	registry    *RouteRegistry
+	domain      string
+	name        string
+	prefix      string
+	middlewares []contractshttp.Middleware
+	// the aliases of the middlewares, empty for the ones that are not resolved from an alias
+	aliases         []string
 	lastMiddlewares []contractshttp.Middleware
+	// the timeout and the body limit of the routes, they override the global ones when they are set
+	timeout   *time.Duration
+	bodyLimit *int64
 }
 
-func NewGroup(config config.Config, instance gin.IRouter, prefix string, middlewares []contractshttp.Middleware, lastMiddlewares []contractshttp.Middleware) contractsroute.Router {
+func NewGroup(config config.Config, instance gin.IRouter, registry *RouteRegistry, prefix string, middlewares []contractshttp.Middleware, lastMiddlewares []contractshttp.Middleware) contractsroute.Router {
 	return &Group{
 		config:          config,
 		instance:        instance,
+		registry:        registry,
 		prefix:          prefix,
 		middlewares:     middlewares,
 		lastMiddlewares: lastMiddlewares,
@@ -32,92 +43,180 @@ func NewGroup(config config.Config, instance gin.IRouter, prefix string, middlew
 }
 
 func (r *Group) Group(handler contractsroute.GroupFunc) {
-	handler(NewGroup(r.config, r.instance, r.getFullPath(""), r.middlewares, r.lastMiddlewares))
+	handler(r.newGroup(r.getFullPath("")))
 }
 
 func (r *Group) Prefix(path string) contractsroute.Router {
-	return NewGroup(r.config, r.instance, r.getFullPath(path), r.middlewares, r.lastMiddlewares)
+	return r.newGroup(r.getFullPath(path))
 }
 
 func (r *Group) Middleware(middlewares ...contractshttp.Middleware) contractsroute.Router {
-	return NewGroup(r.config, r.instance, r.getFullPath(""), append(r.middlewares, middlewares...), r.lastMiddlewares)
+	group := r.newGroup(r.getFullPath(""))
+	group.middlewares = slices.Concat(r.middlewares, middlewares)
+	group.aliases = slices.Concat(r.getAliases(), make([]string, len(middlewares)))
+
+	return group
 }
 
-func (r *Group) Any(path string, handler contractshttp.HandlerFunc) contractsroute.Action {
-	r.WithMiddlewares().Any(r.getGinFullPath(path), []gin.HandlerFunc{handlerToGinHandler(handler)}...)
+// MiddlewareByName appends the middlewares resolved from the aliases or middleware groups, e.g.
+// MiddlewareByName("web", "throttle:60,1"), it panics if a middleware is not defined.
+func (r *Group) MiddlewareByName(names ...string) contractsroute.Router {
+	middlewares, aliases := r.registry.mustResolveMiddlewares(names)
+	group := r.newGroup(r.getFullPath(""))
+	group.middlewares = slices.Concat(r.middlewares, middlewares)
+	group.aliases = slices.Concat(r.getAliases(), aliases)
+
+	return group
+}
+
+// Domain restricts the routes of the group to the requests whose host matches the domain, the {key}
+// placeholders of the domain can be read by ContextRequest.Route, e.g. Domain("{tenant}.example.com").
+// A request is handled by the first domain that matches both its host and path.
+func (r *Group) Domain(domain string) contractsroute.Router {
+	group := r.newGroup(r.getFullPath(""))
+	group.domain = domain
+
+	return group
+}
+
+// Timeout sets the timeout of the routes in the group, it overrides http.request_timeout, Timeout(0) disables it.
+func (r *Group) Timeout(timeout time.Duration) contractsroute.Router {
+	group := r.newGroup(r.getFullPath(""))
+	group.timeout = &timeout
+
+	return group
+}
+
+// BodyLimit sets the max size of the request bodies of the routes in the group in bytes, it overrides
+// http.drivers.ginx.body_limit.
+func (r *Group) BodyLimit(bytes int64) contractsroute.Router {
+	group := r.newGroup(r.getFullPath(""))
+	group.bodyLimit = &bytes
+
+	return group
+}
+
+// Name prepends the prefix to the names of the routes in the group, the prefixes of nested groups are
+// composed, e.g. Name("admin.").Name("users.") names the index route admin.users.index.
+func (r *Group) Name(prefix string) contractsroute.Router {
+	group := r.newGroup(r.getFullPath(""))
+	group.name = r.name + prefix
+
+	return group
+}
 
-	return NewAction(contractshttp.MethodAny, r.getFullPath(path), r.getHandlerName(handler))
+// Fallback handles the requests that don't match any route under the prefix of the group, the middlewares
+// of the group run before the handler. The fallback of the longest prefix handles a request.
+func (r *Group) Fallback(handler contractshttp.HandlerFunc) {
+	handlers := middlewaresToGinHandlers(slices.Concat(r.registry.sortMiddlewares(r.middlewares, r.getAliases()), r.lastMiddlewares))
+	r.registry.setFallback(r.domain, r.prefix, append(handlers, handlerToGinHandler(handler)))
+}
+
+func (r *Group) Any(path string, handler contractshttp.HandlerFunc) contractsroute.Action {
+	return r.addRoute(contractshttp.MethodAny, path, handler)
 }
 
 func (r *Group) Get(path string, handler contractshttp.HandlerFunc) contractsroute.Action {
-	ginFullPath := r.getGinFullPath(path)
-	r.WithMiddlewares().GET(ginFullPath, []gin.HandlerFunc{handlerToGinHandler(handler)}...)
-	r.WithMiddlewares().HEAD(ginFullPath, []gin.HandlerFunc{handlerToGinHandler(handler)}...)
+	fullPath := r.getFullPath(path)
 
-	return NewAction(contractshttp.MethodGet, r.getFullPath(path), r.getHandlerName(handler))
+	return newGroupAction(r, contractshttp.MethodGet, fullPath, r.getHandlerName(handler),
+		actionRoute{method: contractshttp.MethodGet, path: fullPath, handler: handler},
+		actionRoute{method: contractshttp.MethodHead, path: fullPath, handler: handler},
+	)
 }
 
 func (r *Group) Post(path string, handler contractshttp.HandlerFunc) contractsroute.Action {
-	r.WithMiddlewares().POST(r.getGinFullPath(path), []gin.HandlerFunc{handlerToGinHandler(handler)}...)
-
-	return NewAction(contractshttp.MethodPost, r.getFullPath(path), r.getHandlerName(handler))
+	return r.addRoute(contractshttp.MethodPost, path, handler)
 }
 
 func (r *Group) Delete(path string, handler contractshttp.HandlerFunc) contractsroute.Action {
-	r.WithMiddlewares().DELETE(r.getGinFullPath(path), []gin.HandlerFunc{handlerToGinHandler(handler)}...)
-
-	return NewAction(contractshttp.MethodDelete, r.getFullPath(path), r.getHandlerName(handler))
+	return r.addRoute(contractshttp.MethodDelete, path, handler)
 }
 
 func (r *Group) Patch(path string, handler contractshttp.HandlerFunc) contractsroute.Action {
-	r.WithMiddlewares().PATCH(r.getGinFullPath(path), []gin.HandlerFunc{handlerToGinHandler(handler)}...)
-
-	return NewAction(contractshttp.MethodPatch, r.getFullPath(path), r.getHandlerName(handler))
+	return r.addRoute(contractshttp.MethodPatch, path, handler)
 }
 
 func (r *Group) Put(path string, handler contractshttp.HandlerFunc) contractsroute.Action {
-	r.WithMiddlewares().PUT(r.getGinFullPath(path), []gin.HandlerFunc{handlerToGinHandler(handler)}...)
-
-	return NewAction(contractshttp.MethodPut, r.getFullPath(path), r.getHandlerName(handler))
+	return r.addRoute(contractshttp.MethodPut, path, handler)
 }
 
 func (r *Group) Options(path string, handler contractshttp.HandlerFunc) contractsroute.Action {
-	r.WithMiddlewares().OPTIONS(r.getGinFullPath(path), []gin.HandlerFunc{handlerToGinHandler(handler)}...)
+	return r.addRoute(contractshttp.MethodOptions, path, handler)
+}
+
+// Redirect registers a route that redirects the requests to the target, the {key} placeholders of the
+// target are filled by the parameters of the path, e.g. Redirect("/posts/{id}", "/articles/{id}", 302).
+func (r *Group) Redirect(from, to string, code int) contractsroute.Action {
+	fullPath := r.getFullPath(from)
+	handler := func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().Redirect(code, fillRouteParameters(to, ctx.Request().Route))
+	}
 
-	return NewAction(contractshttp.MethodOptions, r.getFullPath(path), r.getHandlerName(handler))
+	return newGroupAction(r, contractshttp.MethodAny, fullPath, fmt.Sprintf("Redirect %d %s", code, to),
+		actionRoute{method: contractshttp.MethodAny, path: fullPath, handler: handler},
+	)
 }
 
-func (r *Group) Resource(path string, controller contractshttp.ResourceController) contractsroute.Action {
-	ginFullPath := r.getGinFullPath(path)
-	r.WithMiddlewares().GET(ginFullPath, []gin.HandlerFunc{handlerToGinHandler(controller.Index)}...)
-	r.WithMiddlewares().POST(ginFullPath, []gin.HandlerFunc{handlerToGinHandler(controller.Store)}...)
+// PermanentRedirect registers a route that redirects the requests to the target with 301.
+func (r *Group) PermanentRedirect(from, to string) contractsroute.Action {
+	return r.Redirect(from, to, http.StatusMovedPermanently)
+}
 
-	ginFullPathWithID := r.getGinFullPath(path + "/{id}")
-	r.WithMiddlewares().GET(ginFullPathWithID, []gin.HandlerFunc{handlerToGinHandler(controller.Show)}...)
-	r.WithMiddlewares().PUT(ginFullPathWithID, []gin.HandlerFunc{handlerToGinHandler(controller.Update)}...)
-	r.WithMiddlewares().PATCH(ginFullPathWithID, []gin.HandlerFunc{handlerToGinHandler(controller.Update)}...)
-	r.WithMiddlewares().DELETE(ginFullPathWithID, []gin.HandlerFunc{handlerToGinHandler(controller.Destroy)}...)
+// View registers a route that renders the view with the data.
+func (r *Group) View(path, view string, data ...any) contractsroute.Action {
+	fullPath := r.getFullPath(path)
+	handler := func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().View().Make(view, data...)
+	}
 
-	return NewAction(contractshttp.MethodResource, r.getFullPath(path), r.getHandlerName(controller))
+	return newGroupAction(r, contractshttp.MethodGet, fullPath, "View "+view,
+		actionRoute{method: contractshttp.MethodGet, path: fullPath, handler: handler},
+		actionRoute{method: contractshttp.MethodHead, path: fullPath, handler: handler},
+	)
+}
+
+// Resource registers the index, create, store, show, edit, update and destroy routes of the controller,
+// the create and edit routes are only registered when the controller implements them. The path can be
+// nested with dots, e.g. users.posts registers /users/{user}/posts/{post}.
+func (r *Group) Resource(path string, controller contractshttp.ResourceController) contractsroute.Action {
+	return newResourceAction(r, path, controller, false)
+}
+
+// ApiResource registers the routes of the controller like Resource, except the create and edit routes.
+func (r *Group) ApiResource(path string, controller contractshttp.ResourceController) contractsroute.Action {
+	return newResourceAction(r, path, controller, true)
 }
 
 func (r *Group) Static(path, root string) contractsroute.Action {
-	fullPath := r.getFullPath(path)
-	r.WithMiddlewares().Static(pathToGinPath(fullPath), root)
+	r.WithMiddlewares().Static(r.getDomainGinPath(path, true), root)
 
-	return NewAction(contractshttp.MethodStatic, fullPath, r.getHandlerName(nil))
+	return r.newStaticAction(contractshttp.MethodStatic, path)
 }
 
 func (r *Group) StaticFile(path, filepath string) contractsroute.Action {
-	r.WithMiddlewares().StaticFile(r.getGinFullPath(path), filepath)
+	r.WithMiddlewares().StaticFile(r.getDomainGinPath(path, false), filepath)
 
-	return NewAction(contractshttp.MethodStaticFile, r.getFullPath(path), r.getHandlerName(nil))
+	return r.newStaticAction(contractshttp.MethodStaticFile, path)
 }
 
 func (r *Group) StaticFS(path string, fs http.FileSystem) contractsroute.Action {
-	r.WithMiddlewares().StaticFS(r.getGinFullPath(path), fs)
+	r.WithMiddlewares().StaticFS(r.getDomainGinPath(path, true), fs)
 
-	return NewAction(contractshttp.MethodStaticFS, r.getFullPath(path), r.getHandlerName(nil))
+	return r.newStaticAction(contractshttp.MethodStaticFS, path)
+}
+
+func (r *Group) addRoute(method, path string, handler contractshttp.HandlerFunc) contractsroute.Action {
+	fullPath := r.getFullPath(path)
+
+	return newGroupAction(r, method, fullPath, r.getHandlerName(handler), actionRoute{method: method, path: fullPath, handler: handler})
+}
+
+func (r *Group) newStaticAction(method, path string) contractsroute.Action {
+	action := NewAction(r.registry, method, r.getDomainPath(r.getFullPath(path)), r.getHandlerName(nil)).(*Action)
+	action.namePrefix = r.name
+
+	return action
 }
 
 func (r *Group) getFullPath(path string) string {
@@ -132,9 +231,55 @@ func (r *Group) getGinFullPath(path string) string {
 	return pathToGinPath(r.getFullPath(path))
 }
 
+// getDomainPath returns the path recorded in the registry, the domain is prepended to the path of
+// a domain route, e.g. {tenant}.example.com/users.
+func (r *Group) getDomainPath(fullPath string) string {
+	if r.domain == "" {
+		return fullPath
+	}
+
+	return r.domain + str.Of(fullPath).Start("/").String()
+}
+
+// getDomainGinPath returns the path registered to gin, the path of a domain route is moved under
+// the prefix of the domain, see RouteRegistry.matchDomain.
+func (r *Group) getDomainGinPath(path string, static bool) string {
+	ginPath := r.getGinFullPath(path)
+	if r.domain == "" {
+		return ginPath
+	}
+
+	return r.registry.addDomainPath(r.domain, ginPath, static)
+}
+
+func (r *Group) newGroup(prefix string) *Group {
+	return &Group{
+		config:          r.config,
+		instance:        r.instance,
+		registry:        r.registry,
+		domain:          r.domain,
+		name:            r.name,
+		prefix:          prefix,
+		middlewares:     r.middlewares,
+		aliases:         r.aliases,
+		lastMiddlewares: r.lastMiddlewares,
+		timeout:         r.timeout,
+		bodyLimit:       r.bodyLimit,
+	}
+}
+
+// getAliases returns the aliases of the middlewares, the groups created by NewGroup don't have them.
+func (r *Group) getAliases() []string {
+	if len(r.aliases) == len(r.middlewares) {
+		return r.aliases
+	}
+
+	return make([]string, len(r.middlewares))
+}
+
 func (r *Group) WithMiddlewares() gin.IRoutes {
 	ginGroup := r.instance.Group("")
-	ginMiddlewares := middlewaresToGinHandlers(append(r.middlewares, r.lastMiddlewares...))
+	ginMiddlewares := middlewaresToGinHandlers(slices.Concat(r.registry.sortMiddlewares(r.middlewares, r.getAliases()), r.lastMiddlewares))
 
 	if len(ginMiddlewares) > 0 {
 		return ginGroup.Use(ginMiddlewares...)
@@ -1,13 +1,23 @@
 package gin
 
 import (
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	contractshttp "github.com/goravel/framework/contracts/http"
 	contractsroute "github.com/goravel/framework/contracts/route"
+	"github.com/goravel/framework/errors"
 	configmocks "github.com/goravel/framework/mocks/config"
+	mockslog "github.com/goravel/framework/mocks/log"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -22,11 +32,11 @@ func TestGroupTestSuite(t *testing.T) {
 }
 
 func (s *GroupTestSuite) SetupTest() {
-	routes = make(map[string]map[string]contractshttp.Info)
-
 	s.mockConfig = configmocks.NewConfig(s.T())
 	s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
 	s.mockConfig.EXPECT().GetInt("http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.ginx.auto_options", true).Return(true).Once()
 	ConfigFacade = s.mockConfig
 
 	route, err := NewRoute(s.mockConfig, nil)
@@ -171,12 +181,246 @@ func (s *GroupTestSuite) TestResource() {
 	s.assert("PATCH", "/resource/1", http.StatusOK, "{\"action\":\"PATCH\",\"id\":\"1\"}")
 	s.assert("DELETE", "/resource/1", http.StatusOK, "{\"action\":\"DELETE\",\"id\":\"1\"}")
 
+	s.assert("HEAD", "/resource", http.StatusOK, "")
+	s.assert("GET", "/resource/create", http.StatusOK, "{\"action\":\"GET\",\"id\":\"create\"}")
+
 	s.Equal(contractshttp.Info{
 		Handler: "github.com/tonidy/goravel-ginx.(resourceController)",
 		Method:  contractshttp.MethodResource,
 		Path:    "/resource",
 		Name:    "resource",
 	}, s.route.Info("resource"))
+	s.Equal(contractshttp.Info{
+		Handler: "github.com/tonidy/goravel-ginx.(resourceController).Index",
+		Method:  "GET|HEAD",
+		Path:    "/resource",
+		Name:    "resource.index",
+	}, s.route.Info("resource.index"))
+	s.Equal(contractshttp.Info{
+		Handler: "github.com/tonidy/goravel-ginx.(resourceController).Update",
+		Method:  "PUT|PATCH",
+		Path:    "/resource/{id}",
+		Name:    "resource.update",
+	}, s.route.Info("resource.update"))
+	s.Len(s.route.GetRoutes(), 6)
+}
+
+func (s *GroupTestSuite) TestResourceOptions() {
+	s.route.Resource("photos", formResourceController{})
+	s.route.ApiResource("api/photos", formResourceController{}).(*Action).Except("destroy").Parameter("photo").WhereNumber("photo").Name("api.photos")
+	s.route.Resource("users.posts", formResourceController{}).(*Action).Only("index", "show", "edit")
+	s.route.Resource("authors.books", formResourceController{}).(*Action).Parameters(map[string]string{"authors": "writer"}).Shallow()
+
+	s.assert("GET", "/photos", http.StatusOK, "{\"name\":\"photos.index\",\"params\":{}}")
+	s.assert("GET", "/photos/create", http.StatusOK, "{\"name\":\"photos.create\",\"params\":{}}")
+	s.assert("GET", "/photos/1/edit", http.StatusOK, "{\"name\":\"photos.edit\",\"params\":{\"id\":\"1\"}}")
+	s.assert("GET", "/api/photos/1", http.StatusOK, "{\"name\":\"api.photos.show\",\"params\":{\"photo\":\"1\"}}")
+	s.assert("GET", "/api/photos/a", http.StatusNotFound, "")
+	s.assert("GET", "/api/photos/1/edit", http.StatusNotFound, "")
+	s.assert("DELETE", "/api/photos/1", http.StatusMethodNotAllowed, "")
+	s.assert("GET", "/users/1/posts/2", http.StatusOK, "{\"name\":\"users.posts.show\",\"params\":{\"post\":\"2\",\"user\":\"1\"}}")
+	s.assert("GET", "/users/1/posts/2/edit", http.StatusOK, "{\"name\":\"users.posts.edit\",\"params\":{\"post\":\"2\",\"user\":\"1\"}}")
+	s.assert("POST", "/users/1/posts", http.StatusMethodNotAllowed, "")
+	s.assert("GET", "/authors/1/books", http.StatusOK, "{\"name\":\"authors.books.index\",\"params\":{\"writer\":\"1\"}}")
+	s.assert("PUT", "/books/2", http.StatusOK, "{\"name\":\"books.update\",\"params\":{\"book\":\"2\"}}")
+
+	var names []string
+	for _, info := range s.route.GetRoutes() {
+		names = append(names, info.Method+" "+info.Path+" "+info.Name)
+	}
+	s.Equal([]string{
+		"GET|HEAD /api/photos api.photos.index",
+		"POST /api/photos api.photos.store",
+	}, names[:2])
+	s.Contains(names, "GET|HEAD /authors/{writer}/books/create authors.books.create")
+	s.Contains(names, "DELETE /books/{book} books.destroy")
+	s.Contains(names, "GET|HEAD /users/{user}/posts users.posts.index")
+	s.NotContains(names, "DELETE /api/photos/{photo} api.photos.destroy")
+}
+
+func (s *GroupTestSuite) TestRedirect() {
+	s.route.Redirect("/posts/{id}", "/articles/{id}", http.StatusFound).Name("posts")
+	s.route.Prefix("docs").(*Group).PermanentRedirect("{page}", "https://goravel.dev/{page}")
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/posts/a b", nil)
+	s.route.ServeHTTP(w, req)
+	s.Equal(http.StatusFound, w.Code)
+	s.Equal("/articles/a%20b", w.Header().Get("Location"))
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/docs/routing", nil)
+	s.route.ServeHTTP(w, req)
+	s.Equal(http.StatusMovedPermanently, w.Code)
+	s.Equal("https://goravel.dev/routing", w.Header().Get("Location"))
+
+	s.Equal(contractshttp.Info{
+		Handler: "Redirect 302 /articles/{id}",
+		Method:  contractshttp.MethodAny,
+		Path:    "/posts/{id}",
+		Name:    "posts",
+	}, s.route.Info("posts"))
+}
+
+func (s *GroupTestSuite) TestFallback() {
+	s.route.Fallback(func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().String(http.StatusNotFound, "web")
+	})
+	s.route.Prefix("api").Middleware(contextMiddleware()).Group(func(route contractsroute.Router) {
+		route.Get("/users/{id}", func(ctx contractshttp.Context) contractshttp.Response {
+			return ctx.Response().Success().String("user")
+		}).(*Action).WhereNumber("id")
+		route.(*Group).Fallback(func(ctx contractshttp.Context) contractshttp.Response {
+			return ctx.Response().Json(http.StatusNotFound, contractshttp.Json{
+				"ctx":  ctx.Value("ctx"),
+				"path": ctx.Request().Path(),
+			})
+		})
+		route.Prefix("v2").(*Group).Fallback(func(ctx contractshttp.Context) contractshttp.Response {
+			return ctx.Response().String(http.StatusNotFound, "v2")
+		})
+	})
+
+	s.assert("GET", "/api/users/1", http.StatusOK, "user")
+	s.assert("GET", "/api/users/a", http.StatusNotFound, "{\"ctx\":\"Goravel\",\"path\":\"/api/users/a\"}")
+	s.assert("GET", "/api/posts", http.StatusNotFound, "{\"ctx\":\"Goravel\",\"path\":\"/api/posts\"}")
+	s.assert("GET", "/api", http.StatusNotFound, "{\"ctx\":\"Goravel\",\"path\":\"/api\"}")
+	s.assert("GET", "/api/v2/posts", http.StatusNotFound, "v2")
+	s.assert("GET", "/apis", http.StatusNotFound, "web")
+	s.assert("GET", "/posts", http.StatusNotFound, "web")
+}
+
+func (s *GroupTestSuite) TestName() {
+	handler := func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().Success().String(ctx.Request().Name())
+	}
+	s.route.Name("admin.").Prefix("admin").Group(func(route contractsroute.Router) {
+		route.Get("/dashboard", handler).Name("dashboard")
+		route.(*Group).Name("users.").Prefix("users").Get("/{id}", handler).Name("show")
+		route.Resource("photos", resourceController{})
+		route.Resource("albums", resourceController{}).Name("pictures")
+		route.Static("static", "./").Name("static")
+	})
+	s.route.Get("/dashboard", handler).Name("dashboard")
+
+	s.assert("GET", "/admin/dashboard", http.StatusOK, "admin.dashboard")
+	s.assert("GET", "/admin/users/1", http.StatusOK, "admin.users.show")
+	s.assert("GET", "/dashboard", http.StatusOK, "dashboard")
+	s.Equal("/admin/photos", s.route.Info("admin.photos.index").Path)
+	s.Equal("/admin/albums", s.route.Info("admin.pictures").Path)
+	s.Equal("/admin/albums", s.route.Info("admin.pictures.index").Path)
+	s.Equal("/admin/static", s.route.Info("admin.static").Path)
+
+	url, err := s.route.Url("admin.users.show", map[string]any{"id": 1}, nil)
+	s.NoError(err)
+	s.Equal("/admin/users/1", url)
+}
+
+func (s *GroupTestSuite) TestBindModel() {
+	type User struct {
+		ID string
+	}
+	s.route.BindModel("user", func(ctx contractshttp.Context, value string) (any, error) {
+		switch value {
+		case "0":
+			return (*User)(nil), nil
+		case "1":
+			return nil, errors.OrmRecordNotFound
+		case "3":
+			return nil, errors.New("connection refused")
+		}
+
+		return &User{ID: value}, nil
+	})
+	s.route.Get("/users/{user}/{tab?}", func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().Success().String(RouteModel[*User](ctx, "user").ID)
+	})
+	s.route.Middleware(abortMiddleware()).Get("/abort/{user}", func(ctx contractshttp.Context) contractshttp.Response {
+		return nil
+	})
+
+	s.assert("GET", "/users/2", http.StatusOK, "2")
+	s.assert("GET", "/users/2/posts", http.StatusOK, "2")
+	s.assert("GET", "/users/0", http.StatusNotFound, "")
+	s.assert("GET", "/users/1", http.StatusNotFound, "")
+	s.assert("GET", "/users/3", http.StatusInternalServerError, "")
+	s.assert("GET", "/abort/2", http.StatusNonAuthoritativeInfo, "")
+}
+
+func (s *GroupTestSuite) TestWhere() {
+	handler := func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().Success().Json(ctx.Request().All())
+	}
+	s.route.Get("/number/{id}", handler).(*Action).WhereNumber("id")
+	s.route.Get("/uuid/{id}", handler).(*Action).WhereUuid("id")
+	s.route.Get("/in/{type}", handler).(*Action).WhereIn("type", []string{"a.b", "c"})
+	s.route.Get("/alpha/{name}/{code}", handler).(*Action).WhereAlpha("name").Where("code", "[a-z]{2}")
+	s.route.Resource("/resource", resourceController{}).(*Action).WhereNumber("id")
+
+	s.assert("GET", "/number/1", http.StatusOK, "{\"id\":\"1\"}")
+	s.assert("GET", "/number/a", http.StatusNotFound, "")
+	s.assert("GET", "/uuid/6ba7b810-9dad-11d1-80b4-00c04fd430c8", http.StatusOK, "{\"id\":\"6ba7b810-9dad-11d1-80b4-00c04fd430c8\"}")
+	s.assert("GET", "/uuid/1", http.StatusNotFound, "")
+	s.assert("GET", "/in/a.b", http.StatusOK, "{\"type\":\"a.b\"}")
+	s.assert("GET", "/in/axb", http.StatusNotFound, "")
+	s.assert("GET", "/alpha/goravel/en", http.StatusOK, "{\"code\":\"en\",\"name\":\"goravel\"}")
+	s.assert("GET", "/alpha/goravel/eng", http.StatusNotFound, "")
+	s.assert("GET", "/alpha/goravel1/en", http.StatusNotFound, "")
+	s.assert("PUT", "/resource/1", http.StatusOK, "{\"action\":null,\"id\":\"1\"}")
+	s.assert("PUT", "/resource/a", http.StatusNotFound, "")
+}
+
+func (s *GroupTestSuite) TestPattern() {
+	s.route.Pattern("id", "[0-9]+")
+	s.route.Fallback(func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().String(http.StatusNotFound, "fallback")
+	})
+
+	handler := func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().Success().String(ctx.Request().Route("id"))
+	}
+	s.route.Get("/pattern/{id}", handler)
+	s.route.Get("/override/{id}", handler).(*Action).WhereAlpha("id")
+
+	s.assert("GET", "/pattern/1", http.StatusOK, "1")
+	s.assert("GET", "/pattern/a", http.StatusNotFound, "fallback")
+	s.assert("GET", "/override/a", http.StatusOK, "a")
+	s.assert("GET", "/override/1", http.StatusNotFound, "fallback")
+}
+
+func (s *GroupTestSuite) TestOptionalParameters() {
+	handler := func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().Success().Json(contractshttp.Json{
+			"slug": ctx.Request().Route("slug"),
+			"page": ctx.Request().RouteInt("page"),
+			"path": ctx.Request().Info().Path,
+		})
+	}
+	s.route.Get("/posts/{slug?}", handler).Name("posts")
+	s.route.Prefix("archive").Get("{slug}/{page?}", handler).(*Action).Default("page", "1").WhereNumber("page")
+
+	s.assert("GET", "/posts", http.StatusOK, "{\"page\":0,\"path\":\"/posts/{slug?}\",\"slug\":\"\"}")
+	s.assert("GET", "/posts/goravel", http.StatusOK, "{\"page\":0,\"path\":\"/posts/{slug?}\",\"slug\":\"goravel\"}")
+	s.assert("GET", "/archive/goravel", http.StatusOK, "{\"page\":1,\"path\":\"/archive/{slug}/{page?}\",\"slug\":\"goravel\"}")
+	s.assert("GET", "/archive/goravel/2", http.StatusOK, "{\"page\":2,\"path\":\"/archive/{slug}/{page?}\",\"slug\":\"goravel\"}")
+	s.assert("GET", "/archive/goravel/a", http.StatusNotFound, "")
+
+	s.PanicsWithError("the optional parameter {slug?} of the route /posts/{slug?}/comments must be trailing", func() {
+		s.route.Get("/posts/{slug?}/comments", handler)
+	})
+
+	routes := s.route.GetRoutes()
+	s.Len(routes, 2)
+	s.Equal("/archive/{slug}/{page?}", routes[0].Path)
+	s.Equal("/posts/{slug?}", routes[1].Path)
+
+	url, err := s.route.Url("posts", nil, nil)
+	s.NoError(err)
+	s.Equal("/posts", url)
+
+	url, err = s.route.Url("posts", map[string]any{"slug": "goravel"}, nil)
+	s.NoError(err)
+	s.Equal("/posts/goravel", url)
 }
 
 func (s *GroupTestSuite) TestStatic() {
@@ -278,6 +522,7 @@ func (s *GroupTestSuite) TestGlobalMiddleware() {
 	s.mockConfig.On("GetString", "http.tls.ssl.cert").Return("").Once()
 	s.mockConfig.On("GetString", "http.tls.ssl.key").Return("").Once()
 	s.mockConfig.On("GetInt", "http.request_timeout", 3).Return(1).Once()
+	s.mockConfig.On("GetInt", "http.drivers.ginx.max_in_flight", 0).Return(0).Once()
 
 	s.route.GlobalMiddleware(func(ctx contractshttp.Context) {
 		ctx.WithValue("global", "goravel")
@@ -292,6 +537,94 @@ func (s *GroupTestSuite) TestGlobalMiddleware() {
 	s.assert("GET", "/global-middleware", http.StatusOK, "{\"global\":\"goravel\"}")
 }
 
+func (s *GroupTestSuite) TestTimeoutAndBodyLimit() {
+	s.mockConfig.On("Get", "cors.paths").Return([]string{})
+	s.mockConfig.On("GetString", "http.tls.host").Return("")
+	s.mockConfig.On("GetString", "http.tls.port").Return("")
+	s.mockConfig.On("GetString", "http.tls.ssl.cert").Return("")
+	s.mockConfig.On("GetString", "http.tls.ssl.key").Return("")
+	s.mockConfig.On("GetInt", "http.request_timeout", 3).Return(1).Once()
+	s.mockConfig.On("GetInt", "http.drivers.ginx.max_in_flight", 0).Return(0).Once()
+	s.route.GlobalMiddleware()
+
+	sleep := func(duration time.Duration) contractshttp.HandlerFunc {
+		return func(ctx contractshttp.Context) contractshttp.Response {
+			select {
+			case <-ctx.Context().Done():
+				return nil
+			case <-time.After(duration):
+				return ctx.Response().Success().String("done")
+			}
+		}
+	}
+	read := func(ctx contractshttp.Context) contractshttp.Response {
+		body, err := io.ReadAll(ctx.Request().Origin().Body)
+		if err != nil {
+			return ctx.Response().Success().String(err.Error())
+		}
+
+		return ctx.Response().Success().String(string(body))
+	}
+
+	s.route.Timeout(50 * time.Millisecond).Group(func(route contractsroute.Router) {
+		route.Get("/timeout/group", sleep(time.Second))
+		route.Get("/timeout/action", sleep(100*time.Millisecond)).(*Action).Timeout(time.Second)
+		// the global timeout is 1 second
+		route.Get("/timeout/disabled", sleep(1100*time.Millisecond)).(*Action).Timeout(0)
+	})
+	s.route.BodyLimit(4).Group(func(route contractsroute.Router) {
+		route.Post("/body-limit/group", read)
+		route.Post("/body-limit/action", read).(*Action).BodyLimit(8)
+		route.Post("/body-limit/json", func(ctx contractshttp.Context) contractshttp.Response {
+			return ctx.Response().Success().Json(ctx.Request().All())
+		})
+	})
+
+	s.assert("GET", "/timeout/group", http.StatusRequestTimeout, "")
+	s.assert("GET", "/timeout/action", http.StatusOK, "done")
+	s.assert("GET", "/timeout/disabled", http.StatusOK, "done")
+
+	tests := []struct {
+		url        string
+		body       string
+		chunked    bool
+		expectCode int
+		expectBody string
+	}{
+		{url: "/body-limit/group", body: "12345", expectCode: http.StatusRequestEntityTooLarge},
+		{url: "/body-limit/group", body: "1234", expectCode: http.StatusOK, expectBody: "1234"},
+		{url: "/body-limit/action", body: "12345", expectCode: http.StatusOK, expectBody: "12345"},
+		// the Content-Length is unknown, so the limit is found when the body is read
+		{url: "/body-limit/group", body: "12345", chunked: true, expectCode: http.StatusRequestEntityTooLarge},
+		{url: "/body-limit/json", body: `{"name":"goravel"}`, chunked: true, expectCode: http.StatusRequestEntityTooLarge},
+	}
+
+	mockLog := mockslog.NewLog(s.T())
+	mockLog.EXPECT().Warningf("request body of %s %s exceeds the limit of %d bytes", "POST", mock.Anything, int64(4)).Times(3)
+	LogFacade = mockLog
+	defer func() {
+		LogFacade = nil
+	}()
+
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("POST", test.url, strings.NewReader(test.body))
+		s.NoError(err)
+		if test.url == "/body-limit/json" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if test.chunked {
+			req.ContentLength = -1
+		}
+
+		s.route.ServeHTTP(w, req)
+		s.Equal(test.expectCode, w.Code, test.url)
+		if test.expectBody != "" {
+			s.Equal(test.expectBody, w.Body.String(), test.url)
+		}
+	}
+}
+
 func (s *GroupTestSuite) TestMiddlewareConflict() {
 	s.route.Prefix("conflict").Group(func(route1 contractsroute.Router) {
 		route1.Middleware(contextMiddleware()).Get("/middleware1/{id}", func(ctx contractshttp.Context) contractshttp.Response {
@@ -313,6 +646,345 @@ func (s *GroupTestSuite) TestMiddlewareConflict() {
 	s.assert("POST", "/conflict/middleware2/1", http.StatusOK, "{\"ctx\":null,\"ctx2\":\"World\",\"id\":\"1\"}")
 }
 
+func (s *GroupTestSuite) TestActionMiddleware() {
+	handler := func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().Success().Json(contractshttp.Json{
+			"ctx":  ctx.Value("ctx"),
+			"ctx1": ctx.Value("ctx1"),
+			"ctx2": ctx.Value("ctx2"),
+		})
+	}
+	middleware, middleware1 := contextMiddleware(), contextMiddleware1()
+	s.route.Middleware(middleware, middleware1).Group(func(route contractsroute.Router) {
+		route.Get("/action-middleware", handler).(*Action).Middleware(contextMiddleware2())
+		route.Get("/without-middleware", handler).(*Action).WithoutMiddleware(middleware)
+		route.Get("/abort-middleware", handler).(*Action).WithoutMiddleware(middleware1).Middleware(abortMiddleware())
+	})
+
+	s.assert("GET", "/action-middleware", http.StatusOK, "{\"ctx\":\"Goravel\",\"ctx1\":\"Hello\",\"ctx2\":\"World\"}")
+	s.assert("GET", "/without-middleware", http.StatusOK, "{\"ctx\":null,\"ctx1\":\"Hello\",\"ctx2\":null}")
+	s.assert("GET", "/abort-middleware", http.StatusNonAuthoritativeInfo, "")
+
+	routes := s.route.GetRoutes()
+	s.Len(routes, 3)
+	s.Equal("github.com/tonidy/goravel-ginx.(*GroupTestSuite).TestActionMiddleware.func1 [github.com/tonidy/goravel-ginx.contextMiddleware, github.com/tonidy/goravel-ginx.abortMiddleware]", routes[0].Handler)
+	s.Equal("github.com/tonidy/goravel-ginx.(*GroupTestSuite).TestActionMiddleware.func1 [github.com/tonidy/goravel-ginx.contextMiddleware, github.com/tonidy/goravel-ginx.contextMiddleware1, github.com/tonidy/goravel-ginx.contextMiddleware2]", routes[1].Handler)
+	s.Equal("github.com/tonidy/goravel-ginx.(*GroupTestSuite).TestActionMiddleware.func1 [github.com/tonidy/goravel-ginx.contextMiddleware1]", routes[2].Handler)
+}
+
+func (s *GroupTestSuite) TestWithoutMiddlewareClosure() {
+	first := func(ctx contractshttp.Context) {
+		ctx.WithValue("first", "Hello")
+		ctx.Request().Next()
+	}
+	second := func(ctx contractshttp.Context) {
+		ctx.WithValue("second", "World")
+		ctx.Request().Next()
+	}
+	handler := func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().Success().Json(contractshttp.Json{
+			"first":  ctx.Value("first"),
+			"second": ctx.Value("second"),
+		})
+	}
+	s.route.Middleware(first, second).Group(func(route contractsroute.Router) {
+		route.Get("/closure", handler)
+		route.Get("/without-closure", handler).(*Action).WithoutMiddleware(first)
+	})
+
+	s.assert("GET", "/closure", http.StatusOK, "{\"first\":\"Hello\",\"second\":\"World\"}")
+	s.assert("GET", "/without-closure", http.StatusOK, "{\"first\":null,\"second\":\"World\"}")
+
+	routes := s.route.GetRoutes()
+	s.Len(routes, 2)
+	// the closures are told apart by their positions, e.g. group_test.go:656
+	name := "github.com/tonidy/goravel-ginx.(*GroupTestSuite).TestWithoutMiddlewareClosure"
+	firstName := fmt.Sprintf("%s (%s)", name, filepath.Base(middlewareLiteral(first)))
+	secondName := fmt.Sprintf("%s (%s)", name, filepath.Base(middlewareLiteral(second)))
+	s.NotEqual(firstName, secondName)
+	s.Equal(name+".func3 ["+firstName+", "+secondName+"]", routes[0].Handler)
+	s.Equal(name+".func3 ["+secondName+"]", routes[1].Handler)
+}
+
+func (s *GroupTestSuite) TestWithoutMiddlewareIdentity() {
+	orderMiddleware := func(name string) contractshttp.Middleware {
+		return func(ctx contractshttp.Context) {
+			value, _ := ctx.Value("order").(string)
+			ctx.WithValue("order", value+name+";")
+			ctx.Request().Next()
+		}
+	}
+	login, api := orderMiddleware("login"), orderMiddleware("api")
+	first, second := &identityMiddleware{name: "first"}, &identityMiddleware{name: "second"}
+	firstHandle, secondHandle := first.Handle, second.Handle
+	handler := func(ctx contractshttp.Context) contractshttp.Response {
+		order, _ := ctx.Value("order").(string)
+
+		return ctx.Response().Success().String(order)
+	}
+	s.route.Middleware(login, api, firstHandle, secondHandle).Group(func(route contractsroute.Router) {
+		route.Get("/without-login", handler).(*Action).WithoutMiddleware(login)
+		route.Get("/without-first", handler).(*Action).WithoutMiddleware(firstHandle)
+		// the closures created by another call are different middlewares
+		route.Get("/without-other", handler).(*Action).WithoutMiddleware(orderMiddleware("login"), first.Handle)
+	})
+
+	s.assert("GET", "/without-login", http.StatusOK, "api;first;second;")
+	s.assert("GET", "/without-first", http.StatusOK, "login;api;second;")
+	s.assert("GET", "/without-other", http.StatusOK, "login;api;first;second;")
+}
+
+func (s *GroupTestSuite) TestActionRegistered() {
+	action := s.route.Get("/registered/{id}", func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().Success().String(ctx.Request().Route("id"))
+	}).(*Action).WhereNumber("id")
+	s.assert("GET", "/registered/1", http.StatusOK, "1")
+
+	// the routes are registered to gin by the first request, they can't be modified afterwards
+	err := errors.New("the route GET|HEAD /registered/{id} is already registered, it can't be modified")
+	s.PanicsWithError(err.Error(), func() {
+		action.WhereAlpha("id")
+	})
+	s.PanicsWithError(err.Error(), func() {
+		action.Middleware(abortMiddleware())
+	})
+	s.PanicsWithError(err.Error(), func() {
+		action.Timeout(time.Second)
+	})
+	s.PanicsWithError(err.Error(), func() {
+		action.BodyLimit(1024)
+	})
+	s.assert("GET", "/registered/1", http.StatusOK, "1")
+
+	action.Name("registered")
+	s.Equal("/registered/{id}", s.route.Info("registered").Path)
+}
+
+func (s *GroupTestSuite) TestDomain() {
+	s.route.Domain("admin.example.com").Get("/users/{id}", func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().Success().String("admin")
+	})
+	s.route.Domain("{tenant}.example.com").Group(func(route contractsroute.Router) {
+		route.Get("/users/{id}", func(ctx contractshttp.Context) contractshttp.Response {
+			return ctx.Response().Success().Json(contractshttp.Json{
+				"tenant": ctx.Request().Route("tenant"),
+				"id":     ctx.Request().Route("id"),
+				"path":   ctx.Request().Path(),
+				"origin": ctx.Request().OriginPath(),
+			})
+		}).Name("tenant.users")
+	})
+	s.route.Get("/users/{id}", func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().Success().String("default")
+	})
+	s.route.Domain("{tenant}.example.com").Get("/dashboard", func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().Success().String(ctx.Request().Route("tenant"))
+	})
+
+	s.assert("GET", "http://goravel.example.com/users/1", http.StatusOK, "{\"id\":\"1\",\"origin\":\"/users/{id}\",\"path\":\"/users/1\",\"tenant\":\"goravel\"}")
+	s.assert("GET", "http://admin.example.com:8080/users/1", http.StatusOK, "admin")
+	s.assert("GET", "http://admin.example.com/dashboard", http.StatusOK, "admin")
+	s.assert("GET", "http://example.com/users/1", http.StatusOK, "default")
+	s.assert("GET", "http://goravel.example.com/dashboard", http.StatusOK, "goravel")
+	s.assert("GET", "http://example.com/dashboard", http.StatusNotFound, "")
+	// the paths of the domain routes can't be requested directly
+	s.assert("GET", "http://example.com/__domain0/users/1", http.StatusNotFound, "")
+	s.assert("GET", "http://goravel.example.com/__domain1/users/1", http.StatusNotFound, "")
+	s.assert("GET", "http://example.com//__domain0/users/1", http.StatusNotFound, "")
+	s.assert("GET", "http://example.com/%5F_domain0/users/1", http.StatusNotFound, "")
+
+	routes := s.route.GetRoutes()
+	s.Len(routes, 4)
+	s.Equal("/users/{id}", routes[0].Path)
+	s.Equal("admin.example.com/users/{id}", routes[1].Path)
+	s.Equal("{tenant}.example.com/dashboard", routes[2].Path)
+	s.Equal("{tenant}.example.com/users/{id}", routes[3].Path)
+
+	url, err := s.route.Url("tenant.users", map[string]any{"tenant": "goravel", "id": 1}, nil)
+	s.NoError(err)
+	s.Equal("//goravel.example.com/users/1", url)
+}
+
+func (s *GroupTestSuite) TestMiddlewareByName() {
+	appendMiddleware := func(parameters ...string) contractshttp.Middleware {
+		return func(ctx contractshttp.Context) {
+			value, _ := ctx.Value("order").(string)
+			ctx.WithValue("order", value+strings.Join(parameters, ","))
+			ctx.Request().Next()
+		}
+	}
+	s.route.AliasMiddleware("session", func(parameters ...string) contractshttp.Middleware {
+		return appendMiddleware("session;")
+	})
+	s.route.AliasMiddleware("auth", func(parameters ...string) contractshttp.Middleware {
+		return appendMiddleware("auth;")
+	})
+	s.route.AliasMiddleware("throttle", appendMiddleware)
+	s.route.AliasMiddleware("abort", func(parameters ...string) contractshttp.Middleware {
+		return abortMiddleware()
+	})
+	s.route.MiddlewareGroup("web", "auth", "session")
+	s.route.MiddlewareGroup("recursive", "recursive")
+	s.route.MiddlewarePriority("session", "auth")
+
+	handler := func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().Success().String(ctx.Value("order").(string))
+	}
+	s.route.MiddlewareByName("web").Get("/web", handler).(*Action).MiddlewareByName("throttle:60,1")
+	s.route.MiddlewareByName("throttle:10").Get("/throttle", handler).(*Action).MiddlewareByName("auth")
+	s.route.MiddlewareByName("abort").Get("/abort", handler)
+	s.route.MiddlewareByName("web", "throttle:10").Get("/without-web", handler).(*Action).WithoutMiddlewareByName("web")
+	s.route.MiddlewareByName("throttle:10", "web", "throttle:20").Get("/without-throttle", handler).(*Action).WithoutMiddlewareByName("throttle")
+
+	s.assert("GET", "/web", http.StatusOK, "session;auth;60,1")
+	s.assert("GET", "/throttle", http.StatusOK, "10auth;")
+	s.assert("GET", "/abort", http.StatusNonAuthoritativeInfo, "")
+	s.assert("GET", "/without-web", http.StatusOK, "10")
+	s.assert("GET", "/without-throttle", http.StatusOK, "session;auth;")
+
+	s.PanicsWithError("middleware undefined is not defined", func() {
+		s.route.MiddlewareByName("undefined")
+	})
+	s.PanicsWithError("middleware group recursive is recursive", func() {
+		s.route.MiddlewareByName("recursive")
+	})
+	// the aliases are resolved when they are declared, not when the routes are registered by the first request
+	s.PanicsWithError("middleware undefined is not defined", func() {
+		s.route.Get("/undefined", handler).(*Action).MiddlewareByName("undefined")
+	})
+	s.PanicsWithError("middleware undefined is not defined", func() {
+		s.route.Get("/without-undefined", handler).(*Action).WithoutMiddlewareByName("undefined")
+	})
+}
+
+func (s *GroupTestSuite) TestMiddlewarePriority() {
+	s.mockConfig.On("Get", "cors.paths").Return([]string{})
+	s.mockConfig.On("GetString", "http.tls.host").Return("")
+	s.mockConfig.On("GetString", "http.tls.port").Return("")
+	s.mockConfig.On("GetString", "http.tls.ssl.cert").Return("")
+	s.mockConfig.On("GetString", "http.tls.ssl.key").Return("")
+	s.mockConfig.On("GetInt", "http.request_timeout", 3).Return(0).Once()
+	s.mockConfig.On("GetInt", "http.drivers.ginx.max_in_flight", 0).Return(0).Once()
+
+	record := func(name string) MiddlewareFactory {
+		return func(parameters ...string) contractshttp.Middleware {
+			return func(ctx contractshttp.Context) {
+				ctx.Response().Header("X-Order", ctx.Response().Origin().Header().Get("X-Order")+name+";")
+				ctx.Request().Next()
+			}
+		}
+	}
+	s.route.AliasMiddleware("session", record("session"))
+	s.route.AliasMiddleware("auth", record("auth"))
+	s.route.AliasMiddleware("global-session", record("global-session"))
+	s.route.AliasMiddleware("global-auth", record("global-auth"))
+	s.route.MiddlewarePriority("global-session", "session", "global-auth", "auth")
+	s.NoError(s.route.GlobalMiddlewareByName("global-auth", "global-session"))
+	s.Error(s.route.GlobalMiddlewareByName("undefined"))
+
+	s.route.MiddlewareByName("auth", "session").Prefix("fallback").(*Group).Fallback(func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().String(http.StatusNotFound, "fallback")
+	})
+	s.route.MiddlewareByName("auth", "session").Static("static", "./")
+	s.route.MiddlewareByName("auth", "session").StaticFile("static-file", "./README.md")
+
+	for _, url := range []string{"/fallback/missing", "/static/README.md", "/static-file"} {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", url, nil)
+		s.NoError(err)
+
+		s.route.ServeHTTP(w, req)
+
+		s.Equal("global-session;global-auth;session;auth;", w.Header().Get("X-Order"), url)
+	}
+}
+
+func (s *GroupTestSuite) TestAfterResponse() {
+	done := make(chan string, 3)
+	s.route.Middleware(Terminable(contextMiddleware2(), func(ctx contractshttp.Context) {
+		done <- "terminate " + ctx.Value("ctx2").(string)
+	})).Get("/after-response", func(ctx contractshttp.Context) contractshttp.Response {
+		ctx.(*Context).AfterResponse(func(ctx contractshttp.Context) {
+			panic("failed callback")
+		})
+		ctx.(*Context).AfterResponse(func(ctx contractshttp.Context) {
+			done <- fmt.Sprintf("after %s %v", ctx.Request().Path(), ctx.Context().Err())
+		})
+
+		return ctx.Response().Success().String("ok")
+	})
+
+	mockLog := mockslog.NewLog(s.T())
+	mockLog.EXPECT().WithContext(mock.Anything).Return(mockLog).Once()
+	mockLog.EXPECT().Request(mock.Anything).Return(mockLog).Once()
+	mockLog.EXPECT().Error("failed callback").Once()
+	LogFacade = mockLog
+	defer func() {
+		LogFacade = nil
+	}()
+
+	s.assert("GET", "/after-response", http.StatusOK, "ok")
+	s.Equal("terminate World", <-done)
+	s.Equal("after /after-response <nil>", <-done)
+	s.route.registry.afterResponses.Wait()
+	s.Empty(done)
+}
+
+func (s *GroupTestSuite) TestAfterResponseOrder() {
+	writer := &eventWriter{}
+	called := make(chan struct{})
+	s.route.Get("/after-response", func(ctx contractshttp.Context) contractshttp.Response {
+		ctx.(*Context).AfterResponse(func(ctx contractshttp.Context) {
+			writer.record("callback")
+			called <- struct{}{}
+		})
+
+		return ctx.Response().Success().String("ok")
+	})
+	s.route.Get("/no-content", func(ctx contractshttp.Context) contractshttp.Response {
+		ctx.(*Context).AfterResponse(func(ctx contractshttp.Context) {
+			writer.record("callback")
+			called <- struct{}{}
+		})
+
+		return ctx.Response().NoContent()
+	})
+	s.route.Timeout(50*time.Millisecond).Get("/timeout", func(ctx contractshttp.Context) contractshttp.Response {
+		<-ctx.Context().Done()
+		ctx.(*Context).AfterResponse(func(ctx contractshttp.Context) {
+			writer.record("callback")
+			called <- struct{}{}
+		})
+
+		return nil
+	})
+
+	tests := []struct {
+		url          string
+		expectCode   int
+		expectEvents []string
+	}{
+		{url: "/after-response", expectCode: http.StatusOK, expectEvents: []string{"header", "write", "flush", "callback"}},
+		// the status is written by gin after the handlers return
+		{url: "/no-content", expectCode: http.StatusNoContent, expectEvents: []string{"header", "flush", "callback"}},
+		// the callback is registered after the timeout response is sent
+		{url: "/timeout", expectCode: http.StatusRequestTimeout, expectEvents: []string{"header", "flush", "callback"}},
+	}
+
+	for _, test := range tests {
+		writer.ResponseRecorder = httptest.NewRecorder()
+		writer.events = nil
+		req, err := http.NewRequest("GET", test.url, nil)
+		s.NoError(err)
+
+		s.route.ServeHTTP(writer, req)
+		<-called
+
+		s.Equal(test.expectCode, writer.Code, test.url)
+		s.Equal(test.expectEvents, writer.recorded(), test.url)
+	}
+}
+
 // https://github.com/goravel/goravel/issues/408
 func (s *GroupTestSuite) TestIssue408() {
 	s.route.Prefix("prefix/{id}").Group(func(route contractsroute.Router) {
@@ -345,6 +1017,43 @@ func (s *GroupTestSuite) assert(method, url string, expectCode int, expectBody s
 	s.Equal(expectCode, w.Code)
 }
 
+// eventWriter records the order of the writes, the flushes and the callbacks.
+type eventWriter struct {
+	*httptest.ResponseRecorder
+	mu     sync.Mutex
+	events []string
+}
+
+func (w *eventWriter) WriteHeader(code int) {
+	w.record("header")
+	w.ResponseRecorder.WriteHeader(code)
+}
+
+func (w *eventWriter) Write(data []byte) (int, error) {
+	w.record("write")
+
+	return w.ResponseRecorder.Write(data)
+}
+
+func (w *eventWriter) Flush() {
+	w.record("flush")
+	w.ResponseRecorder.Flush()
+}
+
+func (w *eventWriter) record(event string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.events = append(w.events, event)
+}
+
+func (w *eventWriter) recorded() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return slices.Clone(w.events)
+}
+
 func abortMiddleware() contractshttp.Middleware {
 	return func(ctx contractshttp.Context) {
 		ctx.Request().Abort(http.StatusNonAuthoritativeInfo)
@@ -362,6 +1071,16 @@ func contextMiddleware() contractshttp.Middleware {
 	}
 }
 
+type identityMiddleware struct {
+	name string
+}
+
+func (r *identityMiddleware) Handle(ctx contractshttp.Context) {
+	value, _ := ctx.Value("order").(string)
+	ctx.WithValue("order", value+r.name+";")
+	ctx.Request().Next()
+}
+
 func contextMiddleware1() contractshttp.Middleware {
 	return func(ctx contractshttp.Context) {
 		ctx.WithValue(2.2, "two point two")
@@ -381,6 +1100,43 @@ func contextMiddleware2() contractshttp.Middleware {
 
 type resourceController struct{}
 
+type formResourceController struct{}
+
+func (c formResourceController) Index(ctx contractshttp.Context) contractshttp.Response {
+	return c.response(ctx)
+}
+
+func (c formResourceController) Create(ctx contractshttp.Context) contractshttp.Response {
+	return c.response(ctx)
+}
+
+func (c formResourceController) Store(ctx contractshttp.Context) contractshttp.Response {
+	return c.response(ctx)
+}
+
+func (c formResourceController) Show(ctx contractshttp.Context) contractshttp.Response {
+	return c.response(ctx)
+}
+
+func (c formResourceController) Edit(ctx contractshttp.Context) contractshttp.Response {
+	return c.response(ctx)
+}
+
+func (c formResourceController) Update(ctx contractshttp.Context) contractshttp.Response {
+	return c.response(ctx)
+}
+
+func (c formResourceController) Destroy(ctx contractshttp.Context) contractshttp.Response {
+	return c.response(ctx)
+}
+
+func (c formResourceController) response(ctx contractshttp.Context) contractshttp.Response {
+	return ctx.Response().Success().Json(contractshttp.Json{
+		"name":   ctx.Request().Info().Name,
+		"params": ctx.Request().All(),
+	})
+}
+
 func (c resourceController) Index(ctx contractshttp.Context) contractshttp.Response {
 	action := ctx.Value("action")
 
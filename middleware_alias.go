@@ -0,0 +1,152 @@
+package gin
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+)
+
+// MiddlewareFactory creates the middleware of an alias, the parameters come from the middleware string,
+// e.g. "throttle:60,1" calls the factory of throttle with "60" and "1".
+type MiddlewareFactory func(parameters ...string) contractshttp.Middleware
+
+type middlewareAliases struct {
+	factories map[string]MiddlewareFactory
+	groups    map[string][]string
+	priority  []string
+}
+
+func newMiddlewareAliases() *middlewareAliases {
+	return &middlewareAliases{
+		factories: map[string]MiddlewareFactory{
+			"throttle":    throttleMiddlewareFactory,
+			"client_cert": clientCertificateMiddlewareFactory,
+		},
+		groups: make(map[string][]string),
+	}
+}
+
+func (r *RouteRegistry) AliasMiddleware(name string, factory MiddlewareFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.middlewares.factories[name] = factory
+}
+
+func (r *RouteRegistry) MiddlewareGroup(name string, middlewares ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.middlewares.groups[name] = middlewares
+}
+
+func (r *RouteRegistry) MiddlewarePriority(names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.middlewares.priority = names
+}
+
+// ResolveMiddlewares resolves the middleware strings to middlewares, a string can be an alias with
+// parameters, e.g. "throttle:60,1", or a middleware group, e.g. "web".
+func (r *RouteRegistry) ResolveMiddlewares(names ...string) ([]contractshttp.Middleware, error) {
+	middlewares, _, err := r.resolveMiddlewares(names)
+
+	return middlewares, err
+}
+
+// resolveMiddlewares returns the resolved middlewares and their aliases, the aliases are used to sort
+// the middlewares by priority.
+func (r *RouteRegistry) resolveMiddlewares(names []string) ([]contractshttp.Middleware, []string, error) {
+	r.mu.RLock()
+	factories := maps.Clone(r.middlewares.factories)
+	groups := maps.Clone(r.middlewares.groups)
+	r.mu.RUnlock()
+
+	return resolveMiddlewares(factories, groups, names, nil)
+}
+
+func (r *RouteRegistry) mustResolveMiddlewares(names []string) ([]contractshttp.Middleware, []string) {
+	middlewares, aliases, err := r.resolveMiddlewares(names)
+	if err != nil {
+		panic(err)
+	}
+
+	return middlewares, aliases
+}
+
+// resolveMiddlewares resolves the middleware strings recursively, parents are the groups being resolved.
+func resolveMiddlewares(factories map[string]MiddlewareFactory, groups map[string][]string, names, parents []string) ([]contractshttp.Middleware, []string, error) {
+	var (
+		middlewares []contractshttp.Middleware
+		aliases     []string
+	)
+	for _, name := range names {
+		if group, exist := groups[name]; exist {
+			if slices.Contains(parents, name) {
+				return nil, nil, fmt.Errorf("middleware group %s is recursive", name)
+			}
+
+			groupMiddlewares, groupAliases, err := resolveMiddlewares(factories, groups, group, append(parents, name))
+			if err != nil {
+				return nil, nil, err
+			}
+
+			middlewares = append(middlewares, groupMiddlewares...)
+			aliases = append(aliases, groupAliases...)
+			continue
+		}
+
+		alias, parameters, _ := strings.Cut(name, ":")
+		factory, exist := factories[alias]
+		if !exist {
+			return nil, nil, fmt.Errorf("middleware %s is not defined", alias)
+		}
+
+		if parameters == "" {
+			middlewares = append(middlewares, factory())
+		} else {
+			middlewares = append(middlewares, factory(strings.Split(parameters, ",")...))
+		}
+		aliases = append(aliases, alias)
+	}
+
+	return middlewares, aliases, nil
+}
+
+// sortMiddlewares sorts the middlewares whose aliases are in the priority list by their priority,
+// the other middlewares keep their positions.
+func (r *RouteRegistry) sortMiddlewares(middlewares []contractshttp.Middleware, aliases []string) []contractshttp.Middleware {
+	r.mu.RLock()
+	priority := r.middlewares.priority
+	r.mu.RUnlock()
+
+	var (
+		positions []int
+		sorted    []int
+	)
+	for i, alias := range aliases {
+		if alias != "" && slices.Contains(priority, alias) {
+			positions = append(positions, i)
+			sorted = append(sorted, i)
+		}
+	}
+
+	if len(positions) < 2 {
+		return middlewares
+	}
+
+	slices.SortStableFunc(sorted, func(a, b int) int {
+		return slices.Index(priority, aliases[a]) - slices.Index(priority, aliases[b])
+	})
+
+	result := slices.Clone(middlewares)
+	for i, position := range positions {
+		result[position] = middlewares[sorted[i]]
+	}
+
+	return result
+}
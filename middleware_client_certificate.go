@@ -0,0 +1,72 @@
+package gin
+
+import (
+	"crypto/x509"
+	"net/http"
+	"path"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+)
+
+// ClientCertificateAuth restricts the routes to the clients of mutual TLS whose verified certificates match one of
+// the identities, an identity matches the subject common name or a DNS, URI, email or IP SAN of the certificate,
+// and it can be a path.Match pattern, e.g. "*.svc.cluster.local" or "spiffe://cluster.local/ns/default/sa/*".
+// Any verified certificate is allowed if no identity is passed. The requests without a verified certificate get
+// 401 Unauthorized, and the ones whose certificates don't match get 403 Forbidden.
+func ClientCertificateAuth(identities ...string) contractshttp.Middleware {
+	return func(ctx contractshttp.Context) {
+		certificate := verifiedClientCertificate(ctx.Request().Origin())
+		if certificate == nil {
+			ctx.Request().Abort(http.StatusUnauthorized)
+			return
+		}
+
+		if len(identities) > 0 && !matchClientCertificate(certificate, identities) {
+			ctx.Request().Abort(http.StatusForbidden)
+			return
+		}
+
+		ctx.Request().Next()
+	}
+}
+
+// clientCertificateMiddlewareFactory creates the ClientCertificateAuth middleware of the "client_cert" alias,
+// e.g. "client_cert:orders.svc.cluster.local,payments.svc.cluster.local".
+func clientCertificateMiddlewareFactory(parameters ...string) contractshttp.Middleware {
+	return ClientCertificateAuth(parameters...)
+}
+
+// verifiedClientCertificate returns the client certificate that's verified by the server, the certificates that
+// are requested but not verified are ignored.
+func verifiedClientCertificate(request *http.Request) *x509.Certificate {
+	if request.TLS == nil || len(request.TLS.VerifiedChains) == 0 || len(request.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+
+	return request.TLS.VerifiedChains[0][0]
+}
+
+func matchClientCertificate(certificate *x509.Certificate, identities []string) bool {
+	names := []string{certificate.Subject.CommonName}
+	names = append(names, certificate.DNSNames...)
+	names = append(names, certificate.EmailAddresses...)
+	for _, uri := range certificate.URIs {
+		names = append(names, uri.String())
+	}
+	for _, ip := range certificate.IPAddresses {
+		names = append(names, ip.String())
+	}
+
+	for _, identity := range identities {
+		for _, name := range names {
+			if name == "" {
+				continue
+			}
+			if matched, err := path.Match(identity, name); err == nil && matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,233 @@
+package gin
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+	configmocks "github.com/goravel/framework/mocks/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCertificateAuth(t *testing.T) {
+	dir := t.TempDir()
+	serverCert, serverKey := writeCertificate(t, dir, "server", 1, "localhost")
+
+	ca, caKey := newCertificateAuthority(t, "ca")
+	otherCA, otherCAKey := newCertificateAuthority(t, "other")
+	caFile := filepath.Join(dir, "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw}), 0644))
+
+	spiffe, err := url.Parse("spiffe://cluster.local/ns/default/sa/orders")
+	require.NoError(t, err)
+	orders := newClientCertificate(t, ca, caKey, &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "orders"},
+		DNSNames: []string{"orders.svc.cluster.local"},
+		URIs:     []*url.URL{spiffe},
+	})
+	payments := newClientCertificate(t, ca, caKey, &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "payments"},
+		DNSNames: []string{"payments.svc.cluster.local"},
+	})
+	unknown := newClientCertificate(t, otherCA, otherCAKey, &x509.Certificate{
+		Subject: pkix.Name{CommonName: "orders"},
+	})
+
+	mockConfig := configmocks.NewConfig(t)
+	mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.auto_options", true).Return(true).Once()
+
+	route, err := NewRoute(mockConfig, nil)
+	require.NoError(t, err)
+
+	route.Get("/identity", func(ctx contractshttp.Context) contractshttp.Response {
+		request := ctx.Request().(*ContextRequest)
+		if request.ClientCertificate() == nil {
+			return ctx.Response().Success().String("anonymous")
+		}
+
+		return ctx.Response().Success().String(request.ClientIdentity())
+	})
+	handler := func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().Success().String("ok")
+	}
+	route.Middleware(ClientCertificateAuth("spiffe://cluster.local/ns/default/sa/*")).Get("/orders", handler)
+	route.MiddlewareByName("client_cert:*.svc.cluster.local").Get("/services", handler)
+	route.Middleware(ClientCertificateAuth()).Get("/any", handler)
+
+	mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+	mockConfig.EXPECT().GetString("http.drivers.ginx.client_auth").Return("verify_if_given").Once()
+	mockConfig.EXPECT().GetString("http.drivers.ginx.client_ca").Return(caFile).Once()
+	mockServerConfig(mockConfig)
+
+	l, err := net.Listen("tcp", "127.0.0.1:3046")
+	require.NoError(t, err)
+
+	done := make(chan error)
+	go func() {
+		done <- route.ListenTLSWithCert(l, serverCert, serverKey)
+	}()
+	defer func() {
+		assert.NoError(t, route.Shutdown())
+		assert.NoError(t, <-done)
+	}()
+
+	get := func(certificate *tls.Certificate, url string) (int, string, error) {
+		config := &tls.Config{InsecureSkipVerify: true}
+		if certificate != nil {
+			// the certificate is sent even if its CA isn't accepted by the server
+			config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+				return certificate, nil
+			}
+		}
+
+		resp, err := (&http.Client{Transport: &http.Transport{TLSClientConfig: config}}).Get("https://127.0.0.1:3046" + url)
+		if err != nil {
+			return 0, "", err
+		}
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+
+		body, err := io.ReadAll(resp.Body)
+
+		return resp.StatusCode, string(body), err
+	}
+
+	assert.EventuallyWithT(t, func(t *assert.CollectT) {
+		_, _, err := get(nil, "/identity")
+		assert.NoError(t, err)
+	}, time.Second, 10*time.Millisecond)
+
+	tests := []struct {
+		name        string
+		certificate *tls.Certificate
+		url         string
+		expectCode  int
+		expectBody  string
+	}{
+		{name: "identity without certificate", url: "/identity", expectCode: http.StatusOK, expectBody: "anonymous"},
+		{name: "identity of uri", certificate: orders, url: "/identity", expectCode: http.StatusOK, expectBody: "spiffe://cluster.local/ns/default/sa/orders"},
+		{name: "identity of dns", certificate: payments, url: "/identity", expectCode: http.StatusOK, expectBody: "payments.svc.cluster.local"},
+		{name: "no certificate", url: "/any", expectCode: http.StatusUnauthorized},
+		{name: "any certificate", certificate: payments, url: "/any", expectCode: http.StatusOK, expectBody: "ok"},
+		{name: "uri matches", certificate: orders, url: "/orders", expectCode: http.StatusOK, expectBody: "ok"},
+		{name: "uri doesn't match", certificate: payments, url: "/orders", expectCode: http.StatusForbidden},
+		{name: "dns matches by alias", certificate: payments, url: "/services", expectCode: http.StatusOK, expectBody: "ok"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			code, body, err := get(test.certificate, test.url)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectCode, code)
+			if test.expectBody != "" {
+				assert.Equal(t, test.expectBody, body)
+			}
+		})
+	}
+
+	t.Run("certificate of unknown CA", func(t *testing.T) {
+		_, _, err := get(unknown, "/identity")
+		assert.Error(t, err)
+	})
+}
+
+func TestRoute_ClientAuth(t *testing.T) {
+	dir := t.TempDir()
+	emptyCA := filepath.Join(dir, "empty.crt")
+	require.NoError(t, os.WriteFile(emptyCA, []byte("empty"), 0644))
+	ca, _ := newCertificateAuthority(t, "ca")
+	caFile := filepath.Join(dir, "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw}), 0644))
+
+	tests := []struct {
+		name             string
+		clientAuth       string
+		clientCA         string
+		expectClientAuth tls.ClientAuthType
+		expectError      string
+	}{
+		{name: "default", expectClientAuth: tls.NoClientCert},
+		{name: "request", clientAuth: "request", expectClientAuth: tls.RequestClientCert},
+		{name: "require", clientAuth: "require", clientCA: caFile, expectClientAuth: tls.RequireAndVerifyClientCert},
+		{name: "require without CA", clientAuth: "require", expectError: "the client CA is required to verify the client certificates"},
+		{name: "verify if given without CA", clientAuth: "verify_if_given", expectError: "the client CA is required to verify the client certificates"},
+		{name: "invalid mode", clientAuth: "optional", expectError: "invalid client auth optional, it should be none, request, require or verify_if_given"},
+		{name: "missing CA", clientAuth: "require", clientCA: filepath.Join(dir, "missing.crt"), expectError: "failed to read the client CA " + filepath.Join(dir, "missing.crt")},
+		{name: "empty CA", clientAuth: "require", clientCA: emptyCA, expectError: "no certificate is found in the client CA " + emptyCA},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockConfig := configmocks.NewConfig(t)
+			mockConfig.EXPECT().GetString("http.drivers.ginx.client_auth").Return(test.clientAuth).Once()
+			if test.clientAuth != "optional" {
+				mockConfig.EXPECT().GetString("http.drivers.ginx.client_ca").Return(test.clientCA).Once()
+			}
+
+			clientAuth, _, err := (&Route{config: mockConfig}).clientAuth()
+			if test.expectError != "" {
+				assert.ErrorContains(t, err, test.expectError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectClientAuth, clientAuth)
+		})
+	}
+}
+
+func newCertificateAuthority(t *testing.T, name string) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return certificate, key
+}
+
+func newClientCertificate(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey, template *x509.Certificate) *tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template.SerialNumber = big.NewInt(2)
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	require.NoError(t, err)
+
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
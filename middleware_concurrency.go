@@ -0,0 +1,202 @@
+package gin
+
+import (
+	"container/list"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+)
+
+// ConcurrencyLimiter limits the requests that are handled at the same time. The requests over the limit
+// wait in a queue for a while, and they are shed with 503 Service Unavailable and the Retry-After header if
+// no slot is released in time. A limiter is shared by all the routes that use its middleware.
+type ConcurrencyLimiter struct {
+	mu           sync.Mutex
+	limit        int
+	maxLimit     int
+	minLimit     int
+	inFlight     int
+	waiters      *list.List
+	queueSize    int
+	queueTimeout time.Duration
+	retryAfter   time.Duration
+	response     func(ctx contractshttp.Context)
+
+	// adaptive limit
+	targetLatency time.Duration
+	lastDecrease  time.Time
+}
+
+// NewConcurrencyLimiter creates a limiter that handles maxInFlight requests at the same time, the other
+// requests are shed immediately unless a queue is set.
+func NewConcurrencyLimiter(maxInFlight int) *ConcurrencyLimiter {
+	maxInFlight = max(maxInFlight, 1)
+
+	return &ConcurrencyLimiter{
+		limit:      maxInFlight,
+		maxLimit:   maxInFlight,
+		minLimit:   maxInFlight,
+		waiters:    list.New(),
+		retryAfter: time.Second,
+	}
+}
+
+// Queue lets size requests wait up to timeout for a slot before they are shed.
+func (r *ConcurrencyLimiter) Queue(size int, timeout time.Duration) *ConcurrencyLimiter {
+	r.queueSize = size
+	r.queueTimeout = timeout
+
+	return r
+}
+
+// Adaptive adjusts the limit between minInFlight and the max in flight by the observed latency, the limit is
+// decreased when the requests take longer than targetLatency, and increased again when they are fast.
+func (r *ConcurrencyLimiter) Adaptive(minInFlight int, targetLatency time.Duration) *ConcurrencyLimiter {
+	r.minLimit = min(max(minInFlight, 1), r.maxLimit)
+	r.targetLatency = targetLatency
+
+	return r
+}
+
+// RetryAfter sets the Retry-After header of the shed requests, it's 1 second by default.
+func (r *ConcurrencyLimiter) RetryAfter(retryAfter time.Duration) *ConcurrencyLimiter {
+	r.retryAfter = retryAfter
+
+	return r
+}
+
+// Response sets the response of the shed requests, it's 503 Service Unavailable by default.
+func (r *ConcurrencyLimiter) Response(callback func(ctx contractshttp.Context)) *ConcurrencyLimiter {
+	r.response = callback
+
+	return r
+}
+
+// Limit returns the current limit, it only changes when the limiter is adaptive.
+func (r *ConcurrencyLimiter) Limit() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.limit
+}
+
+// InFlight returns the number of the requests being handled.
+func (r *ConcurrencyLimiter) InFlight() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.inFlight
+}
+
+// Middleware creates the middleware of the limiter.
+func (r *ConcurrencyLimiter) Middleware() contractshttp.Middleware {
+	return func(ctx contractshttp.Context) {
+		if !r.acquire(ctx) {
+			ctx.Response().Header(HeaderRetryAfter, strconv.Itoa(int(math.Ceil(r.retryAfter.Seconds()))))
+			if r.response != nil {
+				r.response(ctx)
+			} else {
+				ctx.Request().Abort(contractshttp.StatusServiceUnavailable)
+			}
+
+			return
+		}
+
+		start := time.Now()
+		defer func() {
+			r.release(time.Since(start))
+		}()
+
+		ctx.Request().Next()
+	}
+}
+
+// ConcurrencyLimit creates a middleware that handles maxInFlight requests at the same time and lets the
+// same number of requests wait up to queueTimeout, e.g. route.Middleware(ConcurrencyLimit(100, 100*time.Millisecond)).
+func ConcurrencyLimit(maxInFlight int, queueTimeout time.Duration) contractshttp.Middleware {
+	return NewConcurrencyLimiter(maxInFlight).Queue(maxInFlight, queueTimeout).Middleware()
+}
+
+func (r *ConcurrencyLimiter) acquire(ctx contractshttp.Context) bool {
+	r.mu.Lock()
+	if r.inFlight < r.limit {
+		r.inFlight++
+		r.mu.Unlock()
+
+		return true
+	}
+
+	if r.queueTimeout <= 0 || r.waiters.Len() >= r.queueSize {
+		r.mu.Unlock()
+
+		return false
+	}
+
+	// release hands the slot over to the first waiter by closing its channel
+	ready := make(chan struct{})
+	waiter := r.waiters.PushBack(ready)
+	r.mu.Unlock()
+
+	timer := time.NewTimer(r.queueTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-ready:
+		return true
+	case <-timer.C:
+	case <-ctx.Request().Origin().Context().Done():
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	select {
+	case <-ready:
+		// the slot has been handed over while timing out, give it back
+		r.releaseLocked()
+	default:
+		r.waiters.Remove(waiter)
+	}
+
+	return false
+}
+
+func (r *ConcurrencyLimiter) release(latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.targetLatency > 0 {
+		r.adapt(latency)
+	}
+
+	r.releaseLocked()
+}
+
+func (r *ConcurrencyLimiter) releaseLocked() {
+	if r.inFlight <= r.limit && r.waiters.Len() > 0 {
+		close(r.waiters.Remove(r.waiters.Front()).(chan struct{}))
+		return
+	}
+
+	r.inFlight--
+}
+
+// adapt decreases the limit by 10% at most once per target latency when the requests are slow, and increases
+// it by one when a fast request finishes while the limiter is saturated.
+func (r *ConcurrencyLimiter) adapt(latency time.Duration) {
+	if latency > r.targetLatency {
+		if now := time.Now(); now.Sub(r.lastDecrease) >= r.targetLatency {
+			r.limit = max(r.minLimit, int(float64(r.limit)*0.9))
+			r.lastDecrease = now
+		}
+
+		return
+	}
+
+	if r.limit < r.maxLimit && (r.inFlight >= r.limit || r.waiters.Len() > 0) {
+		r.limit++
+	}
+}
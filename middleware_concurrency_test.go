@@ -0,0 +1,124 @@
+package gin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+	configmocks "github.com/goravel/framework/mocks/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	mockConfig := configmocks.NewConfig(t)
+	mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.auto_options", true).Return(true).Once()
+
+	route, err := NewRoute(mockConfig, nil)
+	require.NoError(t, err)
+
+	started := make(chan struct{}, 3)
+	unblock := make(chan struct{})
+	handler := func(ctx contractshttp.Context) contractshttp.Response {
+		started <- struct{}{}
+		<-unblock
+
+		return ctx.Response().Success().String("ok")
+	}
+
+	limiter := NewConcurrencyLimiter(1).Queue(1, time.Minute).RetryAfter(2 * time.Second)
+	route.Middleware(limiter.Middleware()).Get("/limited", handler)
+	route.Middleware(ConcurrencyLimit(1, 10*time.Millisecond)).Get("/shed", handler)
+
+	serve := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", url, nil)
+		require.NoError(t, err)
+		route.ServeHTTP(w, req)
+
+		return w
+	}
+
+	t.Run("queued and shed requests", func(t *testing.T) {
+		var wg sync.WaitGroup
+		codes := make([]int, 2)
+		for i := range codes {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				codes[i] = serve("/limited").Code
+			}()
+
+			if i == 0 {
+				<-started
+			}
+		}
+
+		// the second request is queued, so the third one is shed
+		assert.Eventually(t, func() bool {
+			limiter.mu.Lock()
+			defer limiter.mu.Unlock()
+
+			return limiter.waiters.Len() == 1
+		}, time.Second, time.Millisecond)
+
+		w := serve("/limited")
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "2", w.Header().Get(HeaderRetryAfter))
+
+		unblock <- struct{}{}
+		<-started
+		unblock <- struct{}{}
+		wg.Wait()
+
+		assert.Equal(t, []int{http.StatusOK, http.StatusOK}, codes)
+		assert.Equal(t, 0, limiter.InFlight())
+	})
+
+	t.Run("queue timeout", func(t *testing.T) {
+		done := make(chan int)
+		go func() {
+			done <- serve("/shed").Code
+		}()
+		<-started
+
+		w := serve("/shed")
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "1", w.Header().Get(HeaderRetryAfter))
+
+		unblock <- struct{}{}
+		assert.Equal(t, http.StatusOK, <-done)
+	})
+}
+
+func TestConcurrencyLimiter_Adaptive(t *testing.T) {
+	limiter := NewConcurrencyLimiter(10).Adaptive(2, 10*time.Millisecond)
+
+	limiter.inFlight = 10
+	limiter.release(time.Second)
+	assert.Equal(t, 9, limiter.Limit())
+
+	// the limit is decreased at most once per target latency
+	limiter.release(time.Second)
+	assert.Equal(t, 9, limiter.Limit())
+
+	for i := 0; i < 20; i++ {
+		limiter.inFlight = 10
+		limiter.lastDecrease = time.Time{}
+		limiter.release(time.Second)
+	}
+	assert.Equal(t, 2, limiter.Limit())
+
+	// fast requests increase the limit when the limiter is saturated
+	limiter.inFlight = 3
+	limiter.release(time.Millisecond)
+	assert.Equal(t, 3, limiter.Limit())
+	limiter.release(time.Millisecond)
+	assert.Equal(t, 3, limiter.Limit())
+}
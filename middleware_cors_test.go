@@ -30,6 +30,8 @@ func TestCors(t *testing.T) {
 			setup: func() {
 				mockConfig.On("GetBool", "app.debug").Return(true).Once()
 				mockConfig.On("GetInt", "http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+				mockConfig.On("GetBool", "http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+				mockConfig.On("GetBool", "http.drivers.ginx.auto_options", true).Return(true).Once()
 				mockConfig.On("Get", "cors.paths").Return([]string{"*"}).Once()
 				mockConfig.On("Get", "cors.allowed_methods").Return([]string{"*"}).Once()
 				mockConfig.On("Get", "cors.allowed_origins").Return([]string{"*"}).Once()
@@ -52,11 +54,14 @@ func TestCors(t *testing.T) {
 			setup: func() {
 				mockConfig.On("GetBool", "app.debug").Return(true).Once()
 				mockConfig.On("GetInt", "http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+				mockConfig.On("GetBool", "http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+				mockConfig.On("GetBool", "http.drivers.ginx.auto_options", true).Return(true).Once()
 				mockConfig.On("Get", "cors.paths").Return([]string{"api"}).Once()
 				ConfigFacade = mockConfig
 			},
 			assert: func() {
-				assert.Equal(t, http.StatusNotFound, resp.Code)
+				assert.Equal(t, http.StatusNoContent, resp.Code)
+				assert.Equal(t, "POST, OPTIONS", resp.Header().Get("Allow"))
 				assert.Equal(t, "", resp.Header().Get("Access-Control-Allow-Methods"))
 				assert.Equal(t, "", resp.Header().Get("Access-Control-Allow-Origin"))
 				assert.Equal(t, "", resp.Header().Get("Access-Control-Allow-Headers"))
@@ -68,6 +73,8 @@ func TestCors(t *testing.T) {
 			setup: func() {
 				mockConfig.On("GetBool", "app.debug").Return(true).Once()
 				mockConfig.On("GetInt", "http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+				mockConfig.On("GetBool", "http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+				mockConfig.On("GetBool", "http.drivers.ginx.auto_options", true).Return(true).Once()
 				mockConfig.On("Get", "cors.paths").Return([]string{"any/*"}).Once()
 				mockConfig.On("Get", "cors.allowed_methods").Return([]string{"*"}).Once()
 				mockConfig.On("Get", "cors.allowed_origins").Return([]string{"*"}).Once()
@@ -90,6 +97,8 @@ func TestCors(t *testing.T) {
 			setup: func() {
 				mockConfig.On("GetBool", "app.debug").Return(true).Once()
 				mockConfig.On("GetInt", "http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+				mockConfig.On("GetBool", "http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+				mockConfig.On("GetBool", "http.drivers.ginx.auto_options", true).Return(true).Once()
 				mockConfig.On("Get", "cors.paths").Return([]string{"*"}).Once()
 				mockConfig.On("Get", "cors.allowed_methods").Return([]string{"POST"}).Once()
 				mockConfig.On("Get", "cors.allowed_origins").Return([]string{"*"}).Once()
@@ -112,6 +121,8 @@ func TestCors(t *testing.T) {
 			setup: func() {
 				mockConfig.On("GetBool", "app.debug").Return(true).Once()
 				mockConfig.On("GetInt", "http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+				mockConfig.On("GetBool", "http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+				mockConfig.On("GetBool", "http.drivers.ginx.auto_options", true).Return(true).Once()
 				mockConfig.On("Get", "cors.paths").Return([]string{"*"}).Once()
 				mockConfig.On("Get", "cors.allowed_methods").Return([]string{"GET"}).Once()
 				mockConfig.On("Get", "cors.allowed_origins").Return([]string{"*"}).Once()
@@ -134,6 +145,8 @@ func TestCors(t *testing.T) {
 			setup: func() {
 				mockConfig.On("GetBool", "app.debug").Return(true).Once()
 				mockConfig.On("GetInt", "http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+				mockConfig.On("GetBool", "http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+				mockConfig.On("GetBool", "http.drivers.ginx.auto_options", true).Return(true).Once()
 				mockConfig.On("Get", "cors.paths").Return([]string{"*"}).Once()
 				mockConfig.On("Get", "cors.allowed_methods").Return([]string{"*"}).Once()
 				mockConfig.On("Get", "cors.allowed_origins").Return([]string{"https://goravel.com"}).Once()
@@ -156,6 +169,8 @@ func TestCors(t *testing.T) {
 			setup: func() {
 				mockConfig.On("GetBool", "app.debug").Return(true).Once()
 				mockConfig.On("GetInt", "http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+				mockConfig.On("GetBool", "http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+				mockConfig.On("GetBool", "http.drivers.ginx.auto_options", true).Return(true).Once()
 				mockConfig.On("Get", "cors.paths").Return([]string{"*"}).Once()
 				mockConfig.On("Get", "cors.allowed_methods").Return([]string{"*"}).Once()
 				mockConfig.On("Get", "cors.allowed_origins").Return([]string{"https://goravel.dev"}).Once()
@@ -178,6 +193,8 @@ func TestCors(t *testing.T) {
 			setup: func() {
 				mockConfig.On("GetBool", "app.debug").Return(true).Once()
 				mockConfig.On("GetInt", "http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+				mockConfig.On("GetBool", "http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+				mockConfig.On("GetBool", "http.drivers.ginx.auto_options", true).Return(true).Once()
 				mockConfig.On("Get", "cors.paths").Return([]string{"*"}).Once()
 				mockConfig.On("Get", "cors.allowed_methods").Return([]string{"*"}).Once()
 				mockConfig.On("Get", "cors.allowed_origins").Return([]string{"*"}).Once()
@@ -0,0 +1,79 @@
+package gin
+
+import (
+	"crypto/hmac"
+	"net"
+	"net/http"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/support/carbon"
+	"github.com/spf13/cast"
+)
+
+const (
+	signatureKey        = "signature"
+	signatureExpiresKey = "expires"
+)
+
+// ValidateSignature creates middleware to reject the requests whose url signature, generated by Route.SignedUrl,
+// is missing, tampered or expired. The signature is validated by the app.key of the Route serving the request,
+// the same key that signs the url, and the signature of a domain route is only valid on its host.
+func ValidateSignature() contractshttp.Middleware {
+	return func(ctx contractshttp.Context) {
+		if !hasValidSignature(contextRouteRegistry(ctx).appKey(), signatureHost(ctx), ctx.Request().Origin()) {
+			ctx.Request().Abort(http.StatusForbidden)
+			return
+		}
+
+		ctx.Request().Next()
+	}
+}
+
+// signatureHost returns the host covered by the signature, it's only signed for the domain routes.
+func signatureHost(ctx contractshttp.Context) string {
+	context, ok := ctx.(*Context)
+	registry := contextRouteRegistry(ctx)
+	if !ok || registry == nil {
+		return ""
+	}
+
+	route := registry.matchedRoute(context.instance)
+	if route == nil || route.action.group == nil || route.action.group.domain == "" {
+		return ""
+	}
+
+	host := context.instance.Request.Host
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+
+	return host
+}
+
+func hasValidSignature(key, host string, request *http.Request) bool {
+	if key == "" {
+		return false
+	}
+
+	query := request.URL.Query()
+	signature := query.Get(signatureKey)
+	if signature == "" {
+		return false
+	}
+
+	query.Del(signatureKey)
+	url := request.URL.EscapedPath()
+	if len(query) > 0 {
+		url += "?" + query.Encode()
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(urlSignature(key, signaturePayload(host, url)))) {
+		return false
+	}
+
+	if expires := query.Get(signatureExpiresKey); expires != "" {
+		return carbon.Now().Timestamp() <= cast.ToInt64(expires)
+	}
+
+	return true
+}
@@ -0,0 +1,122 @@
+package gin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+	configmocks "github.com/goravel/framework/mocks/config"
+	"github.com/goravel/framework/support/carbon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSignature(t *testing.T) {
+	mockConfig := configmocks.NewConfig(t)
+	mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.auto_options", true).Return(true).Once()
+	mockConfig.EXPECT().GetString("app.key").Return("12345678901234567890123456789012")
+
+	route, err := NewRoute(mockConfig, nil)
+	require.NoError(t, err)
+
+	route.Middleware(ValidateSignature()).Get("/unsubscribe/{user}", func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().Success().String(ctx.Request().Route("user"))
+	}).Name("unsubscribe")
+	route.Domain("{tenant}.example.com").Middleware(ValidateSignature()).Get("/download/{file}", func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().Success().String(ctx.Request().Route("tenant"))
+	}).Name("download")
+
+	carbon.SetTestNow(carbon.FromStdTime(time.Unix(1700000000, 0)))
+	defer carbon.ClearTestNow()
+
+	signedUrl, err := route.SignedUrl("unsubscribe", map[string]any{"user": 1})
+	require.NoError(t, err)
+	temporarySignedUrl, err := route.SignedUrl("unsubscribe", map[string]any{"user": 1, "list": "news"}, time.Unix(1700000060, 0))
+	require.NoError(t, err)
+	assert.Contains(t, temporarySignedUrl, "expires=1700000060")
+	domainSignedUrl, err := route.SignedUrl("download", map[string]any{"tenant": "a", "file": "x"})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(domainSignedUrl, "//a.example.com/download/x?signature="))
+
+	tests := []struct {
+		name       string
+		url        string
+		setup      func()
+		expectCode int
+	}{
+		{
+			name:       "valid signature",
+			url:        signedUrl,
+			expectCode: http.StatusOK,
+		},
+		{
+			name:       "valid temporary signature",
+			url:        temporarySignedUrl,
+			expectCode: http.StatusOK,
+		},
+		{
+			name:       "missing signature",
+			url:        "/unsubscribe/1",
+			expectCode: http.StatusForbidden,
+		},
+		{
+			name:       "tampered parameter",
+			url:        "/unsubscribe/2" + signedUrl[len("/unsubscribe/1"):],
+			expectCode: http.StatusForbidden,
+		},
+		{
+			name:       "valid signature of domain",
+			url:        "http:" + domainSignedUrl,
+			expectCode: http.StatusOK,
+		},
+		{
+			name:       "valid signature of domain with port",
+			url:        "http:" + strings.Replace(domainSignedUrl, "a.example.com", "A.example.com:8080", 1),
+			expectCode: http.StatusOK,
+		},
+		{
+			name:       "signature of domain replayed on another tenant",
+			url:        "http:" + strings.Replace(domainSignedUrl, "a.example.com", "b.example.com", 1),
+			expectCode: http.StatusForbidden,
+		},
+		{
+			name:       "signed by another key",
+			url:        "/unsubscribe/1?signature=" + urlSignature("another key", "/unsubscribe/1"),
+			expectCode: http.StatusForbidden,
+		},
+		{
+			name:       "tampered query",
+			url:        temporarySignedUrl + "&list=all",
+			expectCode: http.StatusForbidden,
+		},
+		{
+			name: "expired signature",
+			url:  temporarySignedUrl,
+			setup: func() {
+				carbon.SetTestNow(carbon.FromStdTime(time.Unix(1700000061, 0)))
+			},
+			expectCode: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.setup != nil {
+				test.setup()
+			}
+
+			w := httptest.NewRecorder()
+			req, err := http.NewRequest("GET", test.url, nil)
+			require.NoError(t, err)
+
+			route.ServeHTTP(w, req)
+			assert.Equal(t, test.expectCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package gin
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	contractshttp "github.com/goravel/framework/contracts/http"
+)
+
+const afterResponseKey = "goravel_afterResponse"
+
+// afterResponse keeps the callbacks registered by AfterResponse, it's stored as a pointer in the keys of
+// the request, so the callbacks registered on a copied context are kept as well.
+type afterResponse struct {
+	mu        sync.Mutex
+	callbacks []func(ctx contractshttp.Context)
+}
+
+// AfterResponse registers a callback that runs after the response has been written and flushed to the
+// client, e.g. flushing metrics or persisting audit logs. The callbacks run in order in a goroutine with a
+// copy of the context, whose context.Context isn't canceled when the request finishes.
+func (c *Context) AfterResponse(callback func(ctx contractshttp.Context)) {
+	value, exist := c.instance.Get(afterResponseKey)
+	if !exist {
+		// the context isn't served by a Route, e.g. Background().
+		return
+	}
+
+	hooks := value.(*afterResponse)
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+
+	hooks.callbacks = append(hooks.callbacks, callback)
+}
+
+// Terminable creates a middleware that runs terminate after the response has been sent, e.g.
+// Terminable(StartSession(), SaveSession). The terminate function gets the same values as the middleware.
+func Terminable(middleware contractshttp.Middleware, terminate func(ctx contractshttp.Context)) contractshttp.Middleware {
+	return func(ctx contractshttp.Context) {
+		if context, ok := ctx.(*Context); ok {
+			context.AfterResponse(terminate)
+		}
+
+		middleware(ctx)
+	}
+}
+
+// afterResponseMiddleware runs the callbacks registered by AfterResponse once the rest of the handlers
+// return and the response is written and flushed by Route.ServeHTTP.
+func afterResponseMiddleware(registry *RouteRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hooks := &afterResponse{}
+		c.Set(afterResponseKey, hooks)
+
+		c.Next()
+
+		hooks.mu.Lock()
+		callbacks := hooks.callbacks
+		hooks.mu.Unlock()
+		if len(callbacks) == 0 {
+			return
+		}
+
+		// the gin context is reused once the request finishes, and the request context is canceled.
+		copied := c.Copy()
+		copied.Request = copied.Request.WithContext(context.WithoutCancel(copied.Request.Context()))
+
+		registry.afterResponses.Add(1)
+		run := func() {
+			go func() {
+				defer registry.afterResponses.Done()
+
+				runAfterResponse(copied, callbacks)
+			}()
+		}
+
+		// the response is written by serveWithTimeout after the handlers return, including the status and the
+		// buffered body, so the callbacks start after it.
+		if writer, ok := c.Request.Context().Value(timeoutWriterKey{}).(*timeoutWriter); ok {
+			writer.onWritten(run)
+			return
+		}
+
+		// gin writes the status after the handlers return, e.g. for 204 No Content, it's written by the flush.
+		if flusher, ok := c.Writer.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		run()
+	}
+}
+
+func runAfterResponse(c *gin.Context, callbacks []func(ctx contractshttp.Context)) {
+	context := NewContext(c)
+	defer func() {
+		contextRequestPool.Put(context.request)
+		contextResponsePool.Put(context.response)
+		context.request = nil
+		context.response = nil
+		contextPool.Put(context)
+	}()
+
+	for _, callback := range callbacks {
+		func() {
+			// a failed callback doesn't prevent the others from running, the response has been sent already.
+			defer func() {
+				if err := recover(); err != nil && LogFacade != nil {
+					LogFacade.WithContext(context).Request(context.Request()).Error(err)
+				}
+			}()
+
+			callback(context)
+		}()
+	}
+}
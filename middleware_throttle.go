@@ -0,0 +1,162 @@
+package gin
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/support/carbon"
+	"github.com/spf13/cast"
+)
+
+const (
+	HeaderRateLimitLimit     = "X-RateLimit-Limit"
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining"
+	HeaderRateLimitReset     = "X-RateLimit-Reset"
+	HeaderRetryAfter         = "Retry-After"
+)
+
+// RateLimiter returns the limits of a request, a request is allowed only if all the limits allow it.
+type RateLimiter func(ctx contractshttp.Context) []*Limit
+
+// RateLimiter registers a named rate limiter that can be used by Throttle, e.g.
+//
+//	route.RateLimiter("api", func(ctx contractshttp.Context) []*Limit {
+//		return []*Limit{PerMinute(60).By(ctx.Request().Ip())}
+//	})
+func (r *RouteRegistry) RateLimiter(name string, limiter RateLimiter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.limiters[name] = limiter
+}
+
+// RateLimitStore sets the default store of the limits, it's an in-memory store by default.
+func (r *RouteRegistry) RateLimitStore(store RateLimitStore) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.limitStore = store
+}
+
+func (r *RouteRegistry) rateLimiter(name string) (RateLimiter, RateLimitStore) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.limiters[name], r.limitStore
+}
+
+// Throttle creates a middleware that limits the requests by the named rate limiter, the rejected requests
+// get 429 Too Many Requests with the Retry-After header. The requests get 500 Internal Server Error if the
+// limiter isn't defined, so a typo in the name doesn't turn the limit off silently.
+func Throttle(name string) contractshttp.Middleware {
+	return func(ctx contractshttp.Context) {
+		registry := contextRouteRegistry(ctx)
+		if registry == nil {
+			ctx.Request().Next()
+			return
+		}
+
+		limiter, store := registry.rateLimiter(name)
+		if limiter == nil {
+			if LogFacade != nil {
+				LogFacade.WithContext(ctx).Request(ctx.Request()).Error(fmt.Sprintf("rate limiter %s isn't defined", name))
+			}
+			ctx.Request().Abort(http.StatusInternalServerError)
+			return
+		}
+
+		throttle(ctx, name, limiter(ctx), store)
+	}
+}
+
+// throttleMiddlewareFactory is the factory of the throttle alias, "throttle:api" uses the api rate limiter,
+// "throttle:60,1" allows 60 requests per minute.
+func throttleMiddlewareFactory(parameters ...string) contractshttp.Middleware {
+	if len(parameters) == 0 {
+		return Throttle("")
+	}
+
+	maxAttempts, err := strconv.Atoi(parameters[0])
+	if err != nil {
+		return Throttle(parameters[0])
+	}
+
+	decayMinutes := 1
+	if len(parameters) > 1 {
+		decayMinutes = cast.ToInt(parameters[1])
+	}
+
+	name := strings.Join(parameters, ",")
+
+	return func(ctx contractshttp.Context) {
+		registry := contextRouteRegistry(ctx)
+		if registry == nil {
+			ctx.Request().Next()
+			return
+		}
+
+		_, store := registry.rateLimiter(name)
+		throttle(ctx, name, []*Limit{PerMinutes(decayMinutes, maxAttempts)}, store)
+	}
+}
+
+func throttle(ctx contractshttp.Context, name string, limits []*Limit, store RateLimitStore) {
+	var allowed *RateLimitResult
+	for index, limit := range limits {
+		limitStore := store
+		if limit.store != nil {
+			limitStore = limit.store
+		}
+
+		key := limit.key
+		if key == "" {
+			key = ctx.Request().Ip() + ":" + ctx.Request().OriginPath()
+		}
+
+		result, err := limit.take(ctx, limitStore, fmt.Sprintf("throttle:%s:%d:%s", name, index, key))
+		if err != nil {
+			// the requests aren't rejected when the store fails
+			if LogFacade != nil {
+				LogFacade.WithContext(ctx).Request(ctx.Request()).Error(err)
+			}
+			continue
+		}
+
+		if !result.Allowed {
+			retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+			ctx.Response().Header(HeaderRateLimitLimit, strconv.Itoa(result.Limit))
+			ctx.Response().Header(HeaderRateLimitRemaining, "0")
+			ctx.Response().Header(HeaderRateLimitReset, strconv.FormatInt(carbon.Now().Timestamp()+int64(retryAfter), 10))
+			ctx.Response().Header(HeaderRetryAfter, strconv.Itoa(retryAfter))
+
+			if limit.response == nil {
+				ctx.Request().Abort(contractshttp.StatusTooManyRequests)
+				return
+			}
+
+			if response := limit.response(ctx); response != nil {
+				_ = response.Render()
+			}
+			if goravelCtx, ok := ctx.(*Context); ok {
+				goravelCtx.instance.Abort()
+			}
+
+			return
+		}
+
+		if allowed == nil || result.Remaining < allowed.Remaining {
+			allowed = &result
+		}
+	}
+
+	if allowed != nil {
+		ctx.Response().Header(HeaderRateLimitLimit, strconv.Itoa(allowed.Limit))
+		ctx.Response().Header(HeaderRateLimitRemaining, strconv.Itoa(allowed.Remaining))
+	}
+
+	ctx.Request().Next()
+}
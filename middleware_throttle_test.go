@@ -0,0 +1,219 @@
+package gin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+	cachemocks "github.com/goravel/framework/mocks/cache"
+	configmocks "github.com/goravel/framework/mocks/config"
+	mockslog "github.com/goravel/framework/mocks/log"
+	"github.com/goravel/framework/support/carbon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThrottle(t *testing.T) {
+	mockConfig := configmocks.NewConfig(t)
+	mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.auto_options", true).Return(true).Once()
+
+	route, err := NewRoute(mockConfig, nil)
+	require.NoError(t, err)
+
+	route.RateLimiter("user", func(ctx contractshttp.Context) []*Limit {
+		return []*Limit{
+			PerMinute(2).By(ctx.Request().Header("User")),
+			PerSecond(5).By(ctx.Request().Header("User")).SlidingWindow(),
+		}
+	})
+	route.RateLimiter("custom", func(ctx contractshttp.Context) []*Limit {
+		return []*Limit{PerHour(1).Response(func(ctx contractshttp.Context) contractshttp.Response {
+			return ctx.Response().String(http.StatusServiceUnavailable, "slow down")
+		})}
+	})
+
+	handler := func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().Success().String("ok")
+	}
+	route.Middleware(Throttle("user")).Get("/user", handler)
+	route.Get("/custom", handler).(*Action).Middleware(Throttle("custom"))
+	route.MiddlewareByName("throttle:1,1").Get("/inline", handler)
+	route.Middleware(Throttle("undefined")).Get("/undefined", handler)
+
+	carbon.SetTestNow(carbon.FromStdTime(time.Unix(1700000000, 0)))
+	defer carbon.ClearTestNow()
+	defer func() {
+		LogFacade = nil
+	}()
+
+	tests := []struct {
+		name          string
+		url           string
+		user          string
+		setup         func()
+		expectCode    int
+		expectBody    string
+		expectHeaders map[string]string
+	}{
+		{
+			name:          "first request of user a",
+			url:           "/user",
+			user:          "a",
+			expectCode:    http.StatusOK,
+			expectHeaders: map[string]string{HeaderRateLimitLimit: "2", HeaderRateLimitRemaining: "1"},
+		},
+		{
+			name:          "second request of user a",
+			url:           "/user",
+			user:          "a",
+			expectCode:    http.StatusOK,
+			expectHeaders: map[string]string{HeaderRateLimitLimit: "2", HeaderRateLimitRemaining: "0"},
+		},
+		{
+			name:       "user a is limited",
+			url:        "/user",
+			user:       "a",
+			expectCode: http.StatusTooManyRequests,
+			expectHeaders: map[string]string{
+				HeaderRateLimitLimit:     "2",
+				HeaderRateLimitRemaining: "0",
+				HeaderRateLimitReset:     "1700000030",
+				HeaderRetryAfter:         "30",
+			},
+		},
+		{
+			name:          "user b isn't limited",
+			url:           "/user",
+			user:          "b",
+			expectCode:    http.StatusOK,
+			expectHeaders: map[string]string{HeaderRateLimitRemaining: "1"},
+		},
+		{
+			name: "a token of user a is refilled",
+			url:  "/user",
+			user: "a",
+			setup: func() {
+				carbon.SetTestNow(carbon.FromStdTime(time.Unix(1700000030, 0)))
+			},
+			expectCode:    http.StatusOK,
+			expectHeaders: map[string]string{HeaderRateLimitRemaining: "0"},
+		},
+		{
+			name:       "first request of custom",
+			url:        "/custom",
+			expectCode: http.StatusOK,
+			expectBody: "ok",
+		},
+		{
+			name:          "custom response",
+			url:           "/custom",
+			expectCode:    http.StatusServiceUnavailable,
+			expectBody:    "slow down",
+			expectHeaders: map[string]string{HeaderRetryAfter: "3600"},
+		},
+		{
+			name:       "first request of inline",
+			url:        "/inline",
+			expectCode: http.StatusOK,
+		},
+		{
+			name:          "inline is limited",
+			url:           "/inline",
+			expectCode:    http.StatusTooManyRequests,
+			expectHeaders: map[string]string{HeaderRetryAfter: "60"},
+		},
+		{
+			name: "undefined limiter",
+			url:  "/undefined",
+			setup: func() {
+				mockLog := mockslog.NewLog(t)
+				mockLog.EXPECT().WithContext(mock.Anything).Return(mockLog).Once()
+				mockLog.EXPECT().Request(mock.Anything).Return(mockLog).Once()
+				mockLog.EXPECT().Error("rate limiter undefined isn't defined").Once()
+				LogFacade = mockLog
+			},
+			expectCode:    http.StatusInternalServerError,
+			expectHeaders: map[string]string{HeaderRateLimitLimit: ""},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.setup != nil {
+				test.setup()
+			}
+
+			w := httptest.NewRecorder()
+			req, err := http.NewRequest("GET", test.url, nil)
+			require.NoError(t, err)
+			req.Header.Set("User", test.user)
+			route.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectCode, w.Code)
+			if test.expectBody != "" {
+				assert.Equal(t, test.expectBody, w.Body.String())
+			}
+			for key, value := range test.expectHeaders {
+				assert.Equal(t, value, w.Header().Get(key), key)
+			}
+		})
+	}
+}
+
+func TestLimit_SlidingWindow(t *testing.T) {
+	carbon.SetTestNow(carbon.FromStdTime(time.Unix(1700000080, 0)))
+	defer carbon.ClearTestNow()
+
+	store := NewMemoryRateLimitStore()
+	limit := PerMinute(10).SlidingWindow()
+	for i := 0; i < 10; i++ {
+		result, err := limit.take(context.Background(), store, "key")
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 9-i, result.Remaining)
+	}
+
+	result, err := limit.take(context.Background(), store, "key")
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.InDelta(t, 26*time.Second, result.RetryAfter, float64(time.Millisecond))
+
+	// the previous window is weighted by 75%, so 2 requests are allowed
+	carbon.SetTestNow(carbon.FromStdTime(time.Unix(1700000115, 0)))
+	for _, allowed := range []bool{true, true, false} {
+		result, err := limit.take(context.Background(), store, "key")
+		require.NoError(t, err)
+		assert.Equal(t, allowed, result.Allowed)
+	}
+}
+
+func TestCacheRateLimitStore(t *testing.T) {
+	mockCache := cachemocks.NewDriver(t)
+	mockLock := cachemocks.NewLock(t)
+	mockCache.EXPECT().Lock("key:lock", time.Second).Return(mockLock).Twice()
+	mockLock.EXPECT().Block(time.Second).Return(true).Twice()
+	mockLock.EXPECT().Release().Return(true).Twice()
+	mockCache.EXPECT().GetString("key").Return("").Once()
+	mockCache.EXPECT().Put("key", `{"tokens":0,"current":1,"previous":0,"time":1}`, time.Minute).Return(nil).Once()
+	mockCache.EXPECT().GetString("key").Return(`{"tokens":0,"current":1,"previous":0,"time":1}`).Once()
+	mockCache.EXPECT().Put("key", `{"tokens":0,"current":2,"previous":0,"time":1}`, time.Minute).Return(nil).Once()
+
+	store := NewCacheRateLimitStore(mockCache)
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, store.Update(context.Background(), "key", time.Minute, func(state *RateLimitState) {
+			state.Current++
+			state.Time = 1
+		}))
+	}
+
+	mockCache.EXPECT().Lock("locked:lock", time.Second).Return(mockLock).Once()
+	mockLock.EXPECT().Block(time.Second).Return(false).Once()
+	assert.EqualError(t, store.Update(context.Background(), "locked", time.Minute, func(state *RateLimitState) {}), "failed to obtain the lock of the rate limit locked")
+}
@@ -1,40 +1,570 @@
 package gin
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
 	"time"
 
+	"github.com/gin-gonic/gin"
 	contractshttp "github.com/goravel/framework/contracts/http"
 	"github.com/goravel/framework/errors"
 )
 
-// Timeout creates middleware to set a timeout for a request
+// Timeout creates middleware to set a timeout for a request. The response of the handler is buffered, it's sent
+// when the handler finishes in time, otherwise it's discarded and the timeout response is sent, see
+// Route.TimeoutResponse. The request finishes as soon as the timeout response is sent, the handler keeps running
+// in the background until it returns, so it should stop when ctx.Context() is done.
+//
+// The buffer is sent once the handler flushes the response or hijacks the connection, e.g. for the streams, SSE
+// and WebSocket, the request doesn't time out afterwards.
+//
+// The requests are only served in the background when the Timeout middleware is given to the route, the group
+// or GlobalMiddleware, e.g. it's wrapped by another middleware otherwise, then the timeout response is sent
+// once the handler returns, unless the handler has written the response.
 func Timeout(timeout time.Duration) contractshttp.Middleware {
 	return func(ctx contractshttp.Context) {
-		timeoutCtx, cancel := context.WithTimeout(ctx.Context(), timeout)
-		defer cancel()
-
+		timeoutCtx := newTimeoutContext(ctx.Context(), timeout)
 		ctx.WithContext(timeoutCtx)
 
-		done := make(chan struct{})
-
-		go func() {
-			defer func() {
-				if err := recover(); err != nil {
-					globalRecoverCallback(ctx, err)
+		expire := func() bool {
+			return true
+		}
+		var abort func()
+		if goravelCtx, ok := ctx.(*Context); ok {
+			if writer, ok := goravelCtx.instance.Request.Context().Value(timeoutWriterKey{}).(*timeoutWriter); ok {
+				// the timeout response is rendered on a copy, the handler may still be using the context.
+				copied := goravelCtx.instance.Copy()
+				expire = func() bool {
+					return writer.expire(copied)
+				}
+				writer.buffer()
+			} else {
+				// the request isn't served by serveWithTimeout, the timeout response is sent once the handler returns.
+				instance := goravelCtx.instance
+				abort = func() {
+					if !instance.Writer.Written() {
+						timeoutResponse(routeRegistryFromContext(instance))(instance)
+					}
 				}
+			}
+		}
 
-				close(done)
-			}()
-			ctx.Request().Next()
+		timer := time.AfterFunc(timeout, func() {
+			if expire() {
+				timeoutCtx.cancel(context.DeadlineExceeded)
+			}
+		})
+		defer func() {
+			timer.Stop()
+			timeoutCtx.stop()
+			timeoutCtx.cancel(context.Canceled)
 		}()
 
-		select {
-		case <-done:
-		case <-timeoutCtx.Done():
-			if errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) {
-				ctx.Request().Abort(contractshttp.StatusRequestTimeout)
+		defer func() {
+			if err := recover(); err != nil {
+				globalRecoverCallback(ctx, err)
 			}
+		}()
+
+		ctx.Request().Next()
+
+		if abort != nil && timeoutCtx.Err() == context.DeadlineExceeded {
+			abort()
 		}
 	}
 }
+
+// timeoutLiteral is the position of the middleware created by Timeout, the routes that have it are served by
+// serveWithTimeout.
+var timeoutLiteral = middlewareLiteral(Timeout(0))
+
+func isTimeoutMiddleware(middleware contractshttp.Middleware) bool {
+	return middlewareLiteral(middleware) == timeoutLiteral
+}
+
+// globalTimeout creates the Timeout middleware installed by GlobalMiddleware, it's skipped for the routes
+// that set their own timeouts by Action.Timeout or Group.Timeout.
+func globalTimeout(timeout time.Duration) contractshttp.Middleware {
+	middleware := Timeout(timeout)
+
+	return func(ctx contractshttp.Context) {
+		if registry := contextRouteRegistry(ctx); registry != nil {
+			if route := registry.matchedRoute(ctx.(*Context).instance); route != nil && route.action.timeout != nil {
+				ctx.Request().Next()
+				return
+			}
+		}
+
+		middleware(ctx)
+	}
+}
+
+// timeoutResponse returns the handler of the timeout response, it's 408 Request Timeout by default.
+func timeoutResponse(registry *RouteRegistry) gin.HandlerFunc {
+	if registry != nil {
+		registry.mu.RLock()
+		handler := registry.timeoutResponse
+		registry.mu.RUnlock()
+
+		if handler != nil {
+			return handlerToGinHandler(handler)
+		}
+	}
+
+	return func(c *gin.Context) {
+		c.AbortWithStatus(contractshttp.StatusRequestTimeout)
+	}
+}
+
+// timeoutMiddleware is the first handler of the engine, it serves the requests that a Timeout middleware applies
+// to by serveWithTimeout, the other requests are served in place, so they don't cost a goroutine. The requests
+// are counted as in flight until their handlers return, even if they time out before.
+func timeoutMiddleware(engine *gin.Engine, registry *RouteRegistry) gin.HandlerFunc {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		defer registry.inFlight.Add(-1)
+
+		registry.serve(engine, writer, request)
+	})
+
+	return func(c *gin.Context) {
+		// the request is routed again in the goroutine, it can't wait for the lock held by itself.
+		unlockRouting(c)
+		if _, ok := c.Request.Context().Value(timeoutWriterKey{}).(*timeoutWriter); ok {
+			return
+		}
+
+		registry.inFlight.Add(1)
+		if !registry.hasTimeout(c) {
+			defer registry.inFlight.Add(-1)
+
+			c.Next()
+			return
+		}
+
+		serveWithTimeout(handler, c.Writer, c.Request)
+		c.Abort()
+	}
+}
+
+// serveWithTimeout serves the request in a goroutine, so the timeout response of the Timeout middleware is sent
+// without waiting for the handler, the goroutine keeps running until the handler returns.
+func serveWithTimeout(handler http.Handler, writer http.ResponseWriter, request *http.Request) {
+	w := &timeoutWriter{
+		writer:  writer,
+		expired: make(chan struct{}),
+	}
+	done := make(chan any, 1)
+
+	go func() {
+		defer func() {
+			done <- recover()
+		}()
+
+		handler.ServeHTTP(w, request.WithContext(context.WithValue(request.Context(), timeoutWriterKey{}, w)))
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			// the response is aborted, the callbacks of onWritten still run, so they aren't leaked.
+			w.written(false)
+			panic(err)
+		}
+	case <-w.expired:
+	}
+
+	w.finish()
+}
+
+type timeoutWriterKey struct{}
+
+// timeoutWriter is the writer of a request served by serveWithTimeout, the writes pass through until a Timeout
+// middleware starts, then they are buffered until the handler finishes, flushes or hijacks the connection.
+// The writes after the timeout are discarded.
+type timeoutWriter struct {
+	mu        sync.Mutex
+	writer    http.ResponseWriter
+	header    http.Header
+	body      bytes.Buffer
+	status    int
+	buffering bool
+	committed bool
+	timedOut  bool
+	finished  bool
+	// isWritten is set once finish sends the response, the callbacks of onWritten run then
+	isWritten  bool
+	afterWrite []func()
+	// the copied context of the Timeout middleware that times out, the timeout response is rendered on it
+	copied  *gin.Context
+	expired chan struct{}
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.buffering || w.timedOut {
+		return w.header
+	}
+
+	return w.writer.Header()
+}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if w.buffering {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+
+		return w.body.Write(data)
+	}
+
+	w.committed = true
+
+	return w.writer.Write(data)
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timedOut {
+		return
+	}
+	if w.buffering {
+		if w.status == 0 {
+			w.status = code
+		}
+
+		return
+	}
+
+	w.committed = true
+	w.writer.WriteHeader(code)
+}
+
+// Flush sends the buffered response, the writes pass through afterwards.
+func (w *timeoutWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timedOut {
+		return
+	}
+
+	w.commit()
+	w.flush()
+}
+
+// Hijack hands the connection over to the handler, the buffered response is discarded.
+func (w *timeoutWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timedOut {
+		return nil, nil, http.ErrHandlerTimeout
+	}
+
+	hijacker, ok := w.writer.(http.Hijacker)
+	if _, supported := w.original().(http.Hijacker); !ok || !supported {
+		return nil, nil, errors.New("the response writer doesn't support hijacking")
+	}
+
+	w.buffering = false
+	w.committed = true
+	w.body.Reset()
+
+	return hijacker.Hijack()
+}
+
+func (w *timeoutWriter) CloseNotify() <-chan bool {
+	if _, ok := w.original().(http.CloseNotifier); ok {
+		return w.writer.(http.CloseNotifier).CloseNotify()
+	}
+
+	return make(chan bool)
+}
+
+// Unwrap returns the original writer for http.ResponseController.
+func (w *timeoutWriter) Unwrap() http.ResponseWriter {
+	return w.writer
+}
+
+// original returns the writer of the server, the writer of gin panics when it doesn't support flushing, hijacking
+// or the close notifications.
+func (w *timeoutWriter) original() http.ResponseWriter {
+	if unwrapper, ok := w.writer.(interface{ Unwrap() http.ResponseWriter }); ok {
+		return unwrapper.Unwrap()
+	}
+
+	return w.writer
+}
+
+func (w *timeoutWriter) flush() {
+	if _, ok := w.original().(http.Flusher); ok {
+		w.writer.(http.Flusher).Flush()
+	}
+}
+
+// buffer starts buffering the response, it does nothing once the response is committed.
+func (w *timeoutWriter) buffer() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.buffering || w.committed || w.timedOut {
+		return
+	}
+
+	w.buffering = true
+	w.header = w.writer.Header().Clone()
+}
+
+// expire times out the request, it returns false if the response is committed or the request is finished.
+func (w *timeoutWriter) expire(copied *gin.Context) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.committed || w.finished || w.timedOut {
+		return false
+	}
+
+	w.timedOut = true
+	w.copied = copied
+	if w.header == nil {
+		w.header = w.writer.Header().Clone()
+	}
+	close(w.expired)
+
+	return true
+}
+
+// finish sends the buffered response, or the timeout response if the request times out, then runs the
+// callbacks of onWritten.
+func (w *timeoutWriter) finish() {
+	w.mu.Lock()
+	w.finished = true
+	timedOut, copied := w.timedOut, w.copied
+	if !timedOut {
+		w.commit()
+	}
+	w.mu.Unlock()
+
+	if timedOut {
+		response := newResponseBuffer(w.writer.Header().Clone())
+		copied.Writer = response
+		timeoutResponse(routeRegistryFromContext(copied))(copied)
+		response.writeTo(w.writer)
+	}
+
+	w.written(true)
+}
+
+// written marks the response as written and runs the callbacks of onWritten, the response is flushed first if
+// flush is true and there are callbacks. The handler of a timed out request may register the callbacks after
+// the writer can't be used, so the timeout response is always flushed.
+func (w *timeoutWriter) written(flush bool) {
+	w.mu.Lock()
+	w.isWritten = true
+	callbacks := w.afterWrite
+	w.afterWrite = nil
+	timedOut := w.timedOut
+	w.mu.Unlock()
+
+	if flush && (timedOut || len(callbacks) > 0) {
+		w.flush()
+	}
+	for _, callback := range callbacks {
+		callback()
+	}
+}
+
+// onWritten runs the callback once the response is written and flushed to the client, it runs immediately if
+// it's already written, e.g. the handler returns after the request times out.
+func (w *timeoutWriter) onWritten(callback func()) {
+	w.mu.Lock()
+	if !w.isWritten {
+		w.afterWrite = append(w.afterWrite, callback)
+		w.mu.Unlock()
+
+		return
+	}
+	w.mu.Unlock()
+
+	callback()
+}
+
+// commit sends the buffered response to the writer, it's called with the lock held.
+func (w *timeoutWriter) commit() {
+	w.committed = true
+	if !w.buffering {
+		return
+	}
+
+	w.buffering = false
+	replaceHeader(w.writer.Header(), w.header)
+	if w.status > 0 {
+		w.writer.WriteHeader(w.status)
+	}
+	if w.body.Len() > 0 {
+		_, _ = w.writer.Write(w.body.Bytes())
+		w.body.Reset()
+	}
+}
+
+// responseBuffer buffers the timeout response, it's rendered by gin, so it implements gin.ResponseWriter.
+type responseBuffer struct {
+	header http.Header
+	body   bytes.Buffer
+	status int
+	size   int
+}
+
+func newResponseBuffer(header http.Header) *responseBuffer {
+	return &responseBuffer{
+		header: header,
+		status: http.StatusOK,
+		size:   -1,
+	}
+}
+
+func (w *responseBuffer) Header() http.Header {
+	return w.header
+}
+
+func (w *responseBuffer) Write(data []byte) (int, error) {
+	w.size = max(w.size, 0) + len(data)
+
+	return w.body.Write(data)
+}
+
+func (w *responseBuffer) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *responseBuffer) WriteHeader(code int) {
+	if code > 0 && w.size < 0 {
+		w.status = code
+	}
+}
+
+func (w *responseBuffer) WriteHeaderNow() {
+	if w.size < 0 {
+		w.size = 0
+	}
+}
+
+func (w *responseBuffer) Status() int {
+	return w.status
+}
+
+func (w *responseBuffer) Size() int {
+	return w.size
+}
+
+func (w *responseBuffer) Written() bool {
+	return w.size >= 0
+}
+
+func (w *responseBuffer) Flush() {}
+
+func (w *responseBuffer) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, errors.New("the connection can't be hijacked by the timeout response")
+}
+
+func (w *responseBuffer) CloseNotify() <-chan bool {
+	return make(chan bool)
+}
+
+func (w *responseBuffer) Pusher() http.Pusher {
+	return nil
+}
+
+// writeTo sends the buffered response to the writer, the Content-Length header is set, so the client doesn't
+// wait for the rest of the response.
+func (w *responseBuffer) writeTo(writer http.ResponseWriter) {
+	header := writer.Header()
+	replaceHeader(header, w.header)
+	header.Set("Content-Length", strconv.Itoa(w.body.Len()))
+
+	writer.WriteHeader(w.status)
+	if w.body.Len() > 0 {
+		_, _ = writer.Write(w.body.Bytes())
+	}
+}
+
+func replaceHeader(header, values http.Header) {
+	for key := range header {
+		if _, exist := values[key]; !exist {
+			header.Del(key)
+		}
+	}
+	for key, value := range values {
+		header[key] = value
+	}
+}
+
+// timeoutContext is done when the request times out. Unlike context.WithTimeout, the deadline doesn't cancel it
+// once the response is committed, so the streams aren't cut off by the timeout.
+type timeoutContext struct {
+	context.Context
+	deadline time.Time
+	done     chan struct{}
+	mu       sync.Mutex
+	err      error
+	stop     func() bool
+}
+
+func newTimeoutContext(parent context.Context, timeout time.Duration) *timeoutContext {
+	ctx := &timeoutContext{
+		Context:  parent,
+		deadline: time.Now().Add(timeout),
+		done:     make(chan struct{}),
+	}
+	ctx.stop = context.AfterFunc(parent, func() {
+		ctx.cancel(parent.Err())
+	})
+
+	return ctx
+}
+
+func (c *timeoutContext) Deadline() (time.Time, bool) {
+	if deadline, ok := c.Context.Deadline(); ok && deadline.Before(c.deadline) {
+		return deadline, true
+	}
+
+	return c.deadline, true
+}
+
+func (c *timeoutContext) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *timeoutContext) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
+
+func (c *timeoutContext) cancel(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return
+	}
+
+	c.err = err
+	close(c.done)
+}
@@ -1,6 +1,10 @@
 package gin
 
 import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -18,11 +22,15 @@ func TestTimeoutMiddleware(t *testing.T) {
 	mockConfig := mocksconfig.NewConfig(t)
 	mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
 	mockConfig.EXPECT().GetInt("http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.auto_options", true).Return(true).Once()
 
 	route, err := NewRoute(mockConfig, nil)
 	require.NoError(t, err)
 
+	timedOut := make(chan struct{})
 	route.Middleware(Timeout(1*time.Second)).Get("/timeout", func(ctx contractshttp.Context) contractshttp.Response {
+		defer close(timedOut)
 		time.Sleep(2 * time.Second)
 		return nil
 	})
@@ -42,6 +50,8 @@ func TestTimeoutMiddleware(t *testing.T) {
 
 		route.ServeHTTP(w, req)
 		assert.Equal(t, contractshttp.StatusRequestTimeout, w.Code)
+
+		<-timedOut
 	})
 
 	t.Run("normal request", func(t *testing.T) {
@@ -85,3 +95,254 @@ func TestTimeoutMiddleware(t *testing.T) {
 		assert.Empty(t, w.Body.String())
 	})
 }
+
+func TestTimeoutMiddleware_Inline(t *testing.T) {
+	mockConfig := mocksconfig.NewConfig(t)
+	mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.auto_options", true).Return(true).Once()
+
+	route, err := NewRoute(mockConfig, nil)
+	require.NoError(t, err)
+
+	served := func(ctx contractshttp.Context) contractshttp.Response {
+		if _, ok := ctx.(*Context).instance.Request.Context().Value(timeoutWriterKey{}).(*timeoutWriter); ok {
+			return ctx.Response().Success().String("background")
+		}
+
+		return ctx.Response().Success().String("inline")
+	}
+	// the Timeout middleware isn't found when it's wrapped by another middleware
+	wrapped := func(ctx contractshttp.Context) {
+		Timeout(50 * time.Millisecond)(ctx)
+	}
+	route.Get("/plain", served)
+	route.Middleware(Timeout(time.Second)).Get("/timeout", served)
+	route.Prefix("group").(*Group).Timeout(time.Second).Get("/timeout", served)
+	route.Middleware(wrapped).Get("/wrapped", served)
+	route.Middleware(wrapped).Get("/wrapped/timeout", func(ctx contractshttp.Context) contractshttp.Response {
+		<-ctx.Context().Done()
+
+		return nil
+	})
+	route.Middleware(wrapped).Get("/wrapped/written", func(ctx contractshttp.Context) contractshttp.Response {
+		<-ctx.Context().Done()
+
+		return ctx.Response().String(http.StatusOK, "late")
+	})
+
+	tests := []struct {
+		url        string
+		expectCode int
+		expectBody string
+	}{
+		{url: "/plain", expectCode: http.StatusOK, expectBody: "inline"},
+		{url: "/timeout", expectCode: http.StatusOK, expectBody: "background"},
+		{url: "/group/timeout", expectCode: http.StatusOK, expectBody: "background"},
+		{url: "/wrapped", expectCode: http.StatusOK, expectBody: "inline"},
+		// the timeout response is sent once the handler returns
+		{url: "/wrapped/timeout", expectCode: http.StatusRequestTimeout},
+		{url: "/wrapped/written", expectCode: http.StatusOK, expectBody: "late"},
+	}
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			route.ServeHTTP(w, httptest.NewRequest(http.MethodGet, test.url, nil))
+			assert.Equal(t, test.expectCode, w.Code)
+			assert.Equal(t, test.expectBody, w.Body.String())
+		})
+	}
+}
+
+func TestTimeoutMiddleware_Response(t *testing.T) {
+	mockConfig := mocksconfig.NewConfig(t)
+	mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.auto_options", true).Return(true).Once()
+
+	route, err := NewRoute(mockConfig, nil)
+	require.NoError(t, err)
+
+	route.TimeoutResponse(func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().Json(http.StatusServiceUnavailable, contractshttp.Json{"message": "timeout"})
+	})
+
+	finished := make(chan struct{})
+	route.Middleware(Timeout(100*time.Millisecond)).Get("/late", func(ctx contractshttp.Context) contractshttp.Response {
+		defer close(finished)
+		<-ctx.Context().Done()
+
+		ctx.Response().Header("late", "true")
+		return ctx.Response().String(http.StatusOK, "late")
+	})
+
+	ignored := make(chan struct{})
+	route.Middleware(Timeout(100*time.Millisecond)).Get("/ignore", func(ctx contractshttp.Context) contractshttp.Response {
+		defer close(ignored)
+		time.Sleep(time.Second)
+
+		return ctx.Response().String(http.StatusOK, "late")
+	})
+
+	route.Middleware(Timeout(time.Second)).Get("/in-time", func(ctx contractshttp.Context) contractshttp.Response {
+		ctx.Response().Header("in-time", "true")
+		return ctx.Response().String(http.StatusCreated, "in time")
+	})
+
+	t.Run("late response is discarded", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/late", nil)
+		require.NoError(t, err)
+
+		route.ServeHTTP(w, req)
+		<-finished
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, `{"message":"timeout"}`, w.Body.String())
+		assert.Equal(t, "21", w.Header().Get("Content-Length"))
+		assert.Empty(t, w.Header().Get("late"))
+	})
+
+	t.Run("the request finishes without waiting for the handler", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/ignore", nil)
+		require.NoError(t, err)
+
+		start := time.Now()
+		route.ServeHTTP(w, req)
+
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, `{"message":"timeout"}`, w.Body.String())
+
+		<-ignored
+		assert.Equal(t, `{"message":"timeout"}`, w.Body.String())
+	})
+
+	t.Run("buffered response is sent", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/in-time", nil)
+		require.NoError(t, err)
+
+		route.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "in time", w.Body.String())
+		assert.Equal(t, "true", w.Header().Get("in-time"))
+	})
+}
+
+func TestTimeoutMiddleware_Commit(t *testing.T) {
+	mockConfig := mocksconfig.NewConfig(t)
+	mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.auto_options", true).Return(true).Once()
+
+	route, err := NewRoute(mockConfig, nil)
+	require.NoError(t, err)
+
+	mockConfig.EXPECT().GetInt("http.drivers.ginx.max_in_flight", 0).Return(0).Once()
+	mockConfig.EXPECT().GetInt("http.request_timeout", 3).Return(1).Once()
+	mockConfig.EXPECT().Get("cors.paths").Return([]string{})
+	mockConfig.EXPECT().GetString("http.tls.host").Return("")
+	mockConfig.EXPECT().GetString("http.tls.port").Return("")
+	mockConfig.EXPECT().GetString("http.tls.ssl.cert").Return("")
+	mockConfig.EXPECT().GetString("http.tls.ssl.key").Return("")
+	ConfigFacade = mockConfig
+	route.GlobalMiddleware()
+
+	// both of them last longer than the default timeout
+	route.Get("/sse", func(ctx contractshttp.Context) contractshttp.Response {
+		ctx.Response().Header("Content-Type", "text/event-stream")
+
+		return ctx.Response().Stream(http.StatusOK, func(w contractshttp.StreamWriter) error {
+			for i := 0; i < 5; i++ {
+				if _, err := w.WriteString(fmt.Sprintf("data: %d\n\n", i)); err != nil {
+					return err
+				}
+				if err := w.Flush(); err != nil {
+					return err
+				}
+				time.Sleep(300 * time.Millisecond)
+			}
+
+			return nil
+		})
+	})
+	route.Get("/ws", func(ctx contractshttp.Context) contractshttp.Response {
+		conn, rw, err := ctx.Response().Writer().(http.Hijacker).Hijack()
+		if err != nil {
+			return ctx.Response().String(http.StatusInternalServerError, err.Error())
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+		_ = rw.Flush()
+
+		message, err := rw.ReadString('\n')
+		if err != nil {
+			return nil
+		}
+
+		_, _ = rw.WriteString("echo " + message)
+		_ = rw.Flush()
+
+		return nil
+	})
+
+	server := httptest.NewServer(route)
+	defer server.Close()
+
+	t.Run("sse", func(t *testing.T) {
+		start := time.Now()
+		resp, err := http.Get(server.URL + "/sse")
+		require.NoError(t, err)
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		reader := bufio.NewReader(resp.Body)
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		assert.Equal(t, "data: 0\n", line)
+		// the events are sent as they are flushed
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+		rest, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, "\ndata: 1\n\ndata: 2\n\ndata: 3\n\ndata: 4\n\n", string(rest))
+	})
+
+	t.Run("websocket upgrade", func(t *testing.T) {
+		conn, err := net.Dial("tcp", server.Listener.Addr().String())
+		require.NoError(t, err)
+		defer func() {
+			_ = conn.Close()
+		}()
+
+		_, err = conn.Write([]byte("GET /ws HTTP/1.1\r\nHost: localhost\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\n"))
+		require.NoError(t, err)
+
+		reader := bufio.NewReader(conn)
+		resp, err := http.ReadResponse(reader, nil)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+
+		time.Sleep(1200 * time.Millisecond)
+
+		_, err = conn.Write([]byte("ping\n"))
+		require.NoError(t, err)
+
+		message, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		assert.Equal(t, "echo ping\n", message)
+	})
+}
@@ -29,6 +29,8 @@ func TestTls(t *testing.T) {
 			setup: func() {
 				mockConfig.On("GetBool", "app.debug").Return(true).Once()
 				mockConfig.On("GetInt", "http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+				mockConfig.On("GetBool", "http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+				mockConfig.On("GetBool", "http.drivers.ginx.auto_options", true).Return(true).Once()
 				mockConfig.On("GetString", "http.tls.host").Return("").Once()
 				mockConfig.On("GetString", "http.tls.port").Return("").Once()
 				mockConfig.On("GetString", "http.tls.ssl.cert").Return("").Once()
@@ -41,6 +43,8 @@ func TestTls(t *testing.T) {
 			setup: func() {
 				mockConfig.On("GetBool", "app.debug").Return(true).Once()
 				mockConfig.On("GetInt", "http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+				mockConfig.On("GetBool", "http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+				mockConfig.On("GetBool", "http.drivers.ginx.auto_options", true).Return(true).Once()
 				mockConfig.On("GetString", "http.tls.host").Return("127.0.0.1").Once()
 				mockConfig.On("GetString", "http.tls.port").Return("3000").Once()
 				mockConfig.On("GetString", "http.tls.ssl.cert").Return("test_ca.crt").Once()
@@ -0,0 +1,269 @@
+package gin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/goravel/framework/contracts/cache"
+	contractshttp "github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/support/carbon"
+)
+
+const (
+	rateLimitTokenBucket   = "token_bucket"
+	rateLimitSlidingWindow = "sliding_window"
+)
+
+// RateLimitStore keeps the state of the rate limits, the state of a key must be updated atomically.
+type RateLimitStore interface {
+	// Update loads the state of the key, calls update with it and saves it for ttl. A new key gets a zero state.
+	Update(ctx context.Context, key string, ttl time.Duration, update func(state *RateLimitState)) error
+}
+
+// RateLimitState is the state of a key, Tokens is used by the token bucket, Current and Previous are the
+// counts of the current and the previous window of the sliding window.
+type RateLimitState struct {
+	Tokens   float64 `json:"tokens"`
+	Current  int     `json:"current"`
+	Previous int     `json:"previous"`
+	// the last refill of the token bucket, or the start of the current window, in nanoseconds
+	Time int64 `json:"time"`
+}
+
+// RateLimitResult is the result of taking an attempt from a limit.
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// Limit allows maxAttempts requests per decay for a key, it uses a token bucket by default.
+type Limit struct {
+	maxAttempts int
+	decay       time.Duration
+	algorithm   string
+	key         string
+	store       RateLimitStore
+	response    contractshttp.HandlerFunc
+}
+
+func NewLimit(maxAttempts int, decay time.Duration) *Limit {
+	return &Limit{
+		maxAttempts: max(maxAttempts, 1),
+		decay:       max(decay, time.Second),
+		algorithm:   rateLimitTokenBucket,
+	}
+}
+
+func PerSecond(maxAttempts int) *Limit {
+	return NewLimit(maxAttempts, time.Second)
+}
+
+func PerMinute(maxAttempts int) *Limit {
+	return NewLimit(maxAttempts, time.Minute)
+}
+
+func PerMinutes(decayMinutes, maxAttempts int) *Limit {
+	return NewLimit(maxAttempts, time.Duration(decayMinutes)*time.Minute)
+}
+
+func PerHour(maxAttempts int) *Limit {
+	return NewLimit(maxAttempts, time.Hour)
+}
+
+func PerDay(maxAttempts int) *Limit {
+	return NewLimit(maxAttempts, 24*time.Hour)
+}
+
+// By sets the key the requests are counted by, e.g. the user id, it's the client IP and the route by default.
+func (r *Limit) By(key string) *Limit {
+	r.key = key
+
+	return r
+}
+
+// SlidingWindow counts the requests in a sliding window instead of a token bucket, it doesn't allow bursts
+// of requests after an idle period.
+func (r *Limit) SlidingWindow() *Limit {
+	r.algorithm = rateLimitSlidingWindow
+
+	return r
+}
+
+// Store sets the store of the limit, it's the in-memory store of the Route by default.
+func (r *Limit) Store(store RateLimitStore) *Limit {
+	r.store = store
+
+	return r
+}
+
+// Response sets the response of the rejected requests, it's 429 Too Many Requests by default. The request is
+// aborted after the response is rendered, e.g.
+//
+//	PerMinute(60).Response(func(ctx contractshttp.Context) contractshttp.Response {
+//		return ctx.Response().Json(http.StatusTooManyRequests, contractshttp.Json{"message": "slow down"})
+//	})
+func (r *Limit) Response(handler contractshttp.HandlerFunc) *Limit {
+	r.response = handler
+
+	return r
+}
+
+// take takes an attempt of the key from the store.
+func (r *Limit) take(ctx context.Context, store RateLimitStore, key string) (RateLimitResult, error) {
+	var result RateLimitResult
+	err := store.Update(ctx, key, 2*r.decay, func(state *RateLimitState) {
+		now := carbon.Now().StdTime().UnixNano()
+		if r.algorithm == rateLimitSlidingWindow {
+			result = r.takeSlidingWindow(state, now)
+		} else {
+			result = r.takeTokenBucket(state, now)
+		}
+	})
+
+	return result, err
+}
+
+func (r *Limit) takeTokenBucket(state *RateLimitState, now int64) RateLimitResult {
+	rate := float64(r.maxAttempts) / float64(r.decay)
+	if state.Time == 0 {
+		state.Tokens = float64(r.maxAttempts)
+	} else if now > state.Time {
+		state.Tokens = math.Min(float64(r.maxAttempts), state.Tokens+float64(now-state.Time)*rate)
+	}
+	state.Time = now
+
+	result := RateLimitResult{Limit: r.maxAttempts}
+	if state.Tokens >= 1 {
+		state.Tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - state.Tokens) / rate)
+	}
+	result.Remaining = int(state.Tokens)
+
+	return result
+}
+
+// takeSlidingWindow weights the count of the previous window by its overlap with the sliding window.
+func (r *Limit) takeSlidingWindow(state *RateLimitState, now int64) RateLimitResult {
+	decay := int64(r.decay)
+	start := now - now%decay
+	if state.Time != start {
+		if state.Time == start-decay {
+			state.Previous = state.Current
+		} else {
+			state.Previous = 0
+		}
+		state.Current = 0
+		state.Time = start
+	}
+
+	elapsed := now - start
+	weight := 1 - float64(elapsed)/float64(decay)
+	count := float64(state.Previous)*weight + float64(state.Current)
+
+	result := RateLimitResult{Limit: r.maxAttempts}
+	if count+1 <= float64(r.maxAttempts) {
+		state.Current++
+		result.Allowed = true
+		result.Remaining = int(float64(r.maxAttempts) - count - 1)
+	} else if state.Previous > 0 && state.Current < r.maxAttempts {
+		// wait until the previous window slides out enough for one more request
+		needed := float64(r.maxAttempts-1-state.Current) / float64(state.Previous)
+		result.RetryAfter = time.Duration(float64(decay)*(1-needed)) - time.Duration(elapsed)
+	} else {
+		// wait until the next window, where the current window becomes the previous one
+		needed := float64(r.maxAttempts-1) / float64(max(state.Current, 1))
+		result.RetryAfter = time.Duration(decay-elapsed) + time.Duration(float64(decay)*(1-needed))
+	}
+
+	return result
+}
+
+// MemoryRateLimitStore keeps the state of the rate limits in memory, the expired keys are removed periodically.
+type MemoryRateLimitStore struct {
+	mu      sync.Mutex
+	states  map[string]*memoryRateLimitState
+	updates int
+}
+
+type memoryRateLimitState struct {
+	state   RateLimitState
+	expires time.Time
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		states: make(map[string]*memoryRateLimitState),
+	}
+}
+
+func (r *MemoryRateLimitStore) Update(_ context.Context, key string, ttl time.Duration, update func(state *RateLimitState)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := carbon.Now().StdTime()
+	r.updates++
+	if r.updates%1000 == 0 {
+		for key, state := range r.states {
+			if now.After(state.expires) {
+				delete(r.states, key)
+			}
+		}
+	}
+
+	state, exist := r.states[key]
+	if !exist || now.After(state.expires) {
+		state = &memoryRateLimitState{}
+		r.states[key] = state
+	}
+
+	update(&state.state)
+	state.expires = now.Add(ttl)
+
+	return nil
+}
+
+// CacheRateLimitStore keeps the state of the rate limits in a cache store, so it's shared by the instances
+// of the application, e.g. NewCacheRateLimitStore(facades.Cache().Store("redis")).
+type CacheRateLimitStore struct {
+	cache       cache.Driver
+	lockTimeout time.Duration
+}
+
+func NewCacheRateLimitStore(cache cache.Driver) *CacheRateLimitStore {
+	return &CacheRateLimitStore{
+		cache:       cache,
+		lockTimeout: time.Second,
+	}
+}
+
+func (r *CacheRateLimitStore) Update(_ context.Context, key string, ttl time.Duration, update func(state *RateLimitState)) error {
+	lock := r.cache.Lock(key+":lock", r.lockTimeout)
+	if !lock.Block(r.lockTimeout) {
+		return fmt.Errorf("failed to obtain the lock of the rate limit %s", key)
+	}
+	defer lock.Release()
+
+	var state RateLimitState
+	if value := r.cache.GetString(key); value != "" {
+		if err := json.Unmarshal([]byte(value), &state); err != nil {
+			return err
+		}
+	}
+
+	update(&state)
+
+	value, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	return r.cache.Put(key, string(value), ttl)
+}
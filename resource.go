@@ -0,0 +1,253 @@
+package gin
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+)
+
+const (
+	resourceIndex   = "index"
+	resourceCreate  = "create"
+	resourceStore   = "store"
+	resourceShow    = "show"
+	resourceEdit    = "edit"
+	resourceUpdate  = "update"
+	resourceDestroy = "destroy"
+)
+
+// ResourceCreateController is implemented by the resource controllers that render the form to create a resource,
+// the create route is registered by Resource, but not by ApiResource.
+type ResourceCreateController interface {
+	Create(ctx contractshttp.Context) contractshttp.Response
+}
+
+// ResourceEditController is implemented by the resource controllers that render the form to edit a resource,
+// the edit route is registered by Resource, but not by ApiResource.
+type ResourceEditController interface {
+	Edit(ctx contractshttp.Context) contractshttp.Response
+}
+
+type actionResource struct {
+	controller contractshttp.ResourceController
+	api        bool
+	// prefix is the path before the resource, segments are the nested resources, e.g. users.posts
+	prefix     string
+	segments   []string
+	name       string
+	parameters map[string]string
+	only       []string
+	except     []string
+	shallow    bool
+	// the keys of the route information registered by the resource, [path, method]
+	infos [][2]string
+}
+
+type resourceRoute struct {
+	action  string
+	methods []string
+	path    string
+	handler contractshttp.HandlerFunc
+	shallow bool
+}
+
+func newResourceAction(group *Group, path string, controller contractshttp.ResourceController, api bool) *Action {
+	fullPath := group.getFullPath(path)
+	prefix, name := "", strings.TrimPrefix(fullPath, "/")
+	if index := strings.LastIndex(fullPath, "/"); index >= 0 {
+		prefix, name = fullPath[:index], fullPath[index+1:]
+	}
+
+	action := &Action{
+		registry:   group.registry,
+		method:     contractshttp.MethodResource,
+		path:       group.getDomainPath(fullPath),
+		group:      group,
+		namePrefix: group.name,
+		wheres:     make(map[string]*regexp.Regexp),
+		defaults:   make(map[string]string),
+		timeout:    group.timeout,
+		bodyLimit:  group.bodyLimit,
+		resource: &actionResource{
+			controller: controller,
+			api:        api,
+			prefix:     prefix,
+			segments:   strings.Split(name, "."),
+			parameters: make(map[string]string),
+		},
+	}
+	action.syncResource()
+	group.registry.addPending(action)
+
+	return action
+}
+
+// Only registers the given actions of the resource, e.g. Only("index", "show").
+func (r *Action) Only(actions ...string) *Action {
+	if r.resource != nil {
+		r.resource.only = actions
+		r.syncResource()
+	}
+
+	return r
+}
+
+// Except registers the actions of the resource except the given ones.
+func (r *Action) Except(actions ...string) *Action {
+	if r.resource != nil {
+		r.resource.except = actions
+		r.syncResource()
+	}
+
+	return r
+}
+
+// Parameter sets the route parameter name of the resource, it's {id} by default, and the singular
+// resource name for a nested resource, e.g. {post} for users.posts.
+func (r *Action) Parameter(name string) *Action {
+	if r.resource != nil {
+		return r.Parameters(map[string]string{r.resource.segments[len(r.resource.segments)-1]: name})
+	}
+
+	return r
+}
+
+// Parameters sets the route parameter names of the nested resources, e.g. {"users": "author"}.
+func (r *Action) Parameters(parameters map[string]string) *Action {
+	if r.resource != nil {
+		for segment, name := range parameters {
+			r.resource.parameters[segment] = name
+		}
+		r.syncResource()
+	}
+
+	return r
+}
+
+// Shallow registers the show, edit, update and destroy routes of a nested resource without the parent
+// resources, e.g. /posts/{post} instead of /users/{user}/posts/{post}.
+func (r *Action) Shallow() *Action {
+	if r.resource != nil {
+		r.resource.shallow = true
+		r.syncResource()
+	}
+
+	return r
+}
+
+// syncResource replaces the routes of the resource in the registry, it's called every time an option
+// of the resource changes.
+func (r *Action) syncResource() {
+	for _, info := range r.resource.infos {
+		r.registry.remove(info[0], info[1])
+	}
+
+	r.resource.infos = nil
+	r.routes = nil
+
+	// the information of the whole resource is kept for compatibility, it's named by Name without a suffix
+	handlerName := r.group.getHandlerName(r.resource.controller)
+	var name string
+	if r.resource.name != "" {
+		name = r.namePrefix + r.resource.name
+	}
+	r.registry.Add(contractshttp.Info{
+		Handler: handlerName,
+		Method:  r.method,
+		Path:    r.path,
+		Name:    name,
+	})
+	r.resource.infos = append(r.resource.infos, [2]string{r.path, r.method})
+
+	for _, route := range r.resource.routes() {
+		infoPath := r.group.getDomainPath(route.path)
+		infoMethod := strings.Join(route.methods, "|")
+
+		r.registry.Add(contractshttp.Info{
+			Handler: handlerName + "." + strings.ToUpper(route.action[:1]) + route.action[1:],
+			Method:  infoMethod,
+			Path:    infoPath,
+			Name:    r.namePrefix + r.resource.routeName(route),
+		})
+		r.registry.addAction(infoPath, infoMethod, r)
+		r.resource.infos = append(r.resource.infos, [2]string{infoPath, infoMethod})
+
+		for _, method := range route.methods {
+			r.routes = append(r.routes, actionRoute{
+				action:     r,
+				method:     method,
+				path:       route.path,
+				handler:    route.handler,
+				infoPath:   infoPath,
+				infoMethod: infoMethod,
+			})
+		}
+	}
+}
+
+func (r *actionResource) routes() []resourceRoute {
+	last := len(r.segments) - 1
+	collection := r.prefix
+	for i, segment := range r.segments[:last] {
+		collection += "/" + segment + "/{" + r.parameter(i) + "}"
+	}
+	collection += "/" + r.segments[last]
+
+	shallow := r.shallow && last > 0
+	member := collection + "/{" + r.parameter(last) + "}"
+	if shallow {
+		member = r.prefix + "/" + r.segments[last] + "/{" + r.parameter(last) + "}"
+	}
+
+	get := []string{contractshttp.MethodGet, contractshttp.MethodHead}
+	routes := []resourceRoute{
+		{action: resourceIndex, methods: get, path: collection, handler: r.controller.Index},
+	}
+	if controller, ok := r.controller.(ResourceCreateController); ok && !r.api {
+		routes = append(routes, resourceRoute{action: resourceCreate, methods: get, path: collection + "/create", handler: controller.Create})
+	}
+	routes = append(routes,
+		resourceRoute{action: resourceStore, methods: []string{contractshttp.MethodPost}, path: collection, handler: r.controller.Store},
+		resourceRoute{action: resourceShow, methods: get, path: member, handler: r.controller.Show, shallow: shallow},
+	)
+	if controller, ok := r.controller.(ResourceEditController); ok && !r.api {
+		routes = append(routes, resourceRoute{action: resourceEdit, methods: get, path: member + "/edit", handler: controller.Edit, shallow: shallow})
+	}
+	routes = append(routes,
+		resourceRoute{action: resourceUpdate, methods: []string{contractshttp.MethodPut, contractshttp.MethodPatch}, path: member, handler: r.controller.Update, shallow: shallow},
+		resourceRoute{action: resourceDestroy, methods: []string{contractshttp.MethodDelete}, path: member, handler: r.controller.Destroy, shallow: shallow},
+	)
+
+	return slices.DeleteFunc(routes, func(route resourceRoute) bool {
+		return (len(r.only) > 0 && !slices.Contains(r.only, route.action)) || slices.Contains(r.except, route.action)
+	})
+}
+
+// parameter returns the route parameter name of the segment, the single resource keeps {id} for compatibility.
+func (r *actionResource) parameter(index int) string {
+	segment := r.segments[index]
+	if name, exist := r.parameters[segment]; exist {
+		return name
+	}
+	if len(r.segments) == 1 {
+		return "id"
+	}
+
+	return singular(segment)
+}
+
+// routeName returns the name of the route, e.g. users.posts.index, the shallow routes are named
+// without the parent resources, e.g. posts.show.
+func (r *actionResource) routeName(route resourceRoute) string {
+	name := r.name
+	if name == "" {
+		name = strings.Join(r.segments, ".")
+		if route.shallow {
+			name = r.segments[len(r.segments)-1]
+		}
+	}
+
+	return name + "." + route.action
+}
@@ -1,6 +1,7 @@
 package gin
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -142,9 +143,22 @@ type StreamResponse struct {
 func (r *StreamResponse) Render() error {
 	r.instance.Status(r.code)
 
+	// the stream is canceled when the request is done or the Route shuts down
+	ctx := r.instance.Request.Context()
+	if registry := routeRegistryFromContext(r.instance); registry != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		stop := context.AfterFunc(registry.streamContext(), cancel)
+		defer func() {
+			stop()
+			cancel()
+		}()
+
+		r.instance.Request = r.instance.Request.WithContext(ctx)
+	}
+
 	w := NewStreamWriter(r.instance)
 
-	ctx := r.instance.Request.Context()
 	for {
 		select {
 		case <-ctx.Done():
@@ -2,12 +2,21 @@ package gin
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/http/httptest"
-	"sort"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -20,9 +29,6 @@ import (
 	"github.com/goravel/framework/support/str"
 )
 
-// map[path]map[method]info
-var routes = make(map[string]map[string]contractshttp.Info)
-
 var globalRecoverCallback func(ctx contractshttp.Context, err any) = func(ctx contractshttp.Context, err any) {
 	LogFacade.WithContext(ctx).Request(ctx.Request()).Error(err)
 	ctx.Request().Abort(http.StatusInternalServerError)
@@ -32,16 +38,38 @@ type Route struct {
 	route.Router
 	config    config.Config
 	instance  *gin.Engine
+	registry  *RouteRegistry
+	mu        sync.Mutex
 	server    *http.Server
 	tlsServer *http.Server
+	ready     atomic.Bool
+	// the SNI certificates added by Certificate
+	certificates []certificateFiles
 }
 
 func NewRoute(config config.Config, parameters map[string]any) (*Route, error) {
 	gin.SetMode(gin.ReleaseMode)
 	gin.DisableBindValidation()
 	engine := gin.New()
-	engine.MaxMultipartMemory = int64(config.GetInt("http.drivers.ginx.body_limit", 4096)) << 10
+	bodyLimit := int64(config.GetInt("http.drivers.ginx.body_limit", 4096)) << 10
+	engine.MaxMultipartMemory = bodyLimit
+
+	registry := NewRouteRegistry()
+	registry.config = config
+	engine.Use(timeoutMiddleware(engine, registry))
+	// the after response callbacks run after the recovery middleware writes the response of a panic.
+	engine.Use(afterResponseMiddleware(registry))
 	engine.Use(gin.Recovery()) // recovery middleware
+	engine.Use(routeRegistryMiddleware(registry))
+	engine.Use(bodyLimitMiddleware(registry, bodyLimit))
+
+	// gin finds the allowed methods of a path only when HandleMethodNotAllowed is enabled,
+	// the automatic OPTIONS responses rely on them as well.
+	methodNotAllowed := config.GetBool("http.drivers.ginx.method_not_allowed", true)
+	autoOptions := config.GetBool("http.drivers.ginx.auto_options", true)
+	engine.HandleMethodNotAllowed = methodNotAllowed || autoOptions
+	engine.NoMethod(noMethodHandler(registry, methodNotAllowed, autoOptions))
+	engine.NoRoute(registry.noRoute)
 
 	if debugLog := getDebugLog(config); debugLog != nil {
 		engine.Use(debugLog)
@@ -72,54 +100,205 @@ func NewRoute(config config.Config, parameters map[string]any) (*Route, error) {
 		}
 	}
 
+	htmlRender, err := bindRouteFunc(engine.HTMLRender, registry)
+	if err != nil {
+		return nil, err
+	}
+	engine.HTMLRender = htmlRender
+
 	return &Route{
 		Router: NewGroup(
 			config,
 			engine.Group("/"),
+			registry,
 			"",
 			[]contractshttp.Middleware{},
 			[]contractshttp.Middleware{ResponseMiddleware()},
 		),
 		config:   config,
 		instance: engine,
+		registry: registry,
 	}, nil
 }
 
 func (r *Route) Fallback(handler contractshttp.HandlerFunc) {
-	r.instance.NoRoute(handlerToGinHandler(handler))
+	r.registry.setFallback("", "", []gin.HandlerFunc{handlerToGinHandler(handler)})
 }
 
+// GetRoutes returns the registered routes, the middlewares of a route are appended to its handler.
 func (r *Route) GetRoutes() []contractshttp.Info {
-	paths := []string{}
-	for path := range routes {
-		paths = append(paths, path)
-	}
+	infos := r.registry.All()
+	middlewares := make([][]contractshttp.Middleware, len(infos))
+	// the positions of the closures by the name of the function that creates them
+	literals := make(map[string]map[string]struct{})
+	for i, info := range infos {
+		action, exist := r.registry.action(info.Path, info.Method)
+		if !exist {
+			continue
+		}
 
-	sort.Strings(paths)
-	methods := []string{contractshttp.MethodGet + "|" + contractshttp.MethodHead, contractshttp.MethodHead, contractshttp.MethodGet, contractshttp.MethodPost, contractshttp.MethodPut, contractshttp.MethodDelete, contractshttp.MethodPatch, contractshttp.MethodOptions, contractshttp.MethodAny, contractshttp.MethodResource, contractshttp.MethodStatic, contractshttp.MethodStaticFile, contractshttp.MethodStaticFS}
+		middlewares[i] = action.Middlewares()
+		for _, middleware := range middlewares[i] {
+			name := middlewareName(middleware)
+			if literals[name] == nil {
+				literals[name] = make(map[string]struct{})
+			}
+			literals[name][middlewareLiteral(middleware)] = struct{}{}
+		}
+	}
 
-	var infos []contractshttp.Info
-	for _, path := range paths {
-		for _, method := range methods {
-			if info, ok := routes[path][method]; ok {
-				infos = append(infos, info)
+	for i, info := range infos {
+		var names []string
+		for _, middleware := range middlewares[i] {
+			name := middlewareName(middleware)
+			// the closures created in the same function are told apart by their positions
+			if len(literals[name]) > 1 {
+				name += " (" + filepath.Base(middlewareLiteral(middleware)) + ")"
 			}
+			names = append(names, name)
+		}
+		if len(names) > 0 {
+			infos[i].Handler = strings.TrimSpace(info.Handler + " [" + strings.Join(names, ", ") + "]")
 		}
 	}
 
 	return infos
 }
 
+// ApiResource registers the routes of the controller except the create and edit routes, see Group.ApiResource.
+func (r *Route) ApiResource(path string, controller contractshttp.ResourceController) route.Action {
+	return r.Router.(*Group).ApiResource(path, controller)
+}
+
+// Redirect registers a route that redirects the requests to the target, see Group.Redirect.
+func (r *Route) Redirect(from, to string, code int) route.Action {
+	return r.Router.(*Group).Redirect(from, to, code)
+}
+
+// PermanentRedirect registers a route that redirects the requests to the target with 301.
+func (r *Route) PermanentRedirect(from, to string) route.Action {
+	return r.Router.(*Group).PermanentRedirect(from, to)
+}
+
+// View registers a route that renders the view with the data.
+func (r *Route) View(path, view string, data ...any) route.Action {
+	return r.Router.(*Group).View(path, view, data...)
+}
+
+// Name prepends the prefix to the names of the routes in the group, see Group.Name.
+func (r *Route) Name(prefix string) route.Router {
+	return r.Router.(*Group).Name(prefix)
+}
+
+// Domain restricts the routes to the requests whose host matches the domain, see Group.Domain.
+func (r *Route) Domain(domain string) route.Router {
+	return r.Router.(*Group).Domain(domain)
+}
+
 func (r *Route) GlobalMiddleware(middlewares ...contractshttp.Middleware) {
-	defaultMiddlewares := []contractshttp.Middleware{Cors(), Tls()}
+	var defaultMiddlewares []contractshttp.Middleware
+	// the requests are limited before the other middlewares, so the shed requests cost nothing.
+	if maxInFlight := r.config.GetInt("http.drivers.ginx.max_in_flight", 0); maxInFlight > 0 {
+		queueTimeout := time.Duration(r.config.GetInt("http.drivers.ginx.queue_timeout", 100)) * time.Millisecond
+		defaultMiddlewares = append(defaultMiddlewares, ConcurrencyLimit(maxInFlight, queueTimeout))
+	}
+	defaultMiddlewares = append(defaultMiddlewares, Cors(), Tls())
 	timeout := time.Duration(r.config.GetInt("http.request_timeout", 3)) * time.Second
 	if timeout > 0 {
-		defaultMiddlewares = append(defaultMiddlewares, Timeout(timeout))
+		defaultMiddlewares = append(defaultMiddlewares, globalTimeout(timeout))
+		r.registry.requestTimeout.Store(true)
 	}
 	middlewares = append(defaultMiddlewares, middlewares...)
 	r.setMiddlewares(middlewares)
 }
 
+// Timeout sets the timeout of the routes in the group, see Group.Timeout.
+func (r *Route) Timeout(timeout time.Duration) route.Router {
+	return r.Router.(*Group).Timeout(timeout)
+}
+
+// BodyLimit sets the max size of the request bodies of the routes in the group, see Group.BodyLimit.
+func (r *Route) BodyLimit(bytes int64) route.Router {
+	return r.Router.(*Group).BodyLimit(bytes)
+}
+
+// AliasMiddleware registers the factory of a middleware alias, the alias can be used in MiddlewareByName
+// with parameters, e.g. "throttle:60,1".
+func (r *Route) AliasMiddleware(name string, factory MiddlewareFactory) {
+	r.registry.AliasMiddleware(name, factory)
+}
+
+// MiddlewareGroup registers a group of middleware aliases, e.g. MiddlewareGroup("web", "session", "csrf").
+func (r *Route) MiddlewareGroup(name string, middlewares ...string) {
+	r.registry.MiddlewareGroup(name, middlewares...)
+}
+
+// MiddlewarePriority sets the order of the middleware aliases in a route, regardless of the order they
+// are declared, e.g. MiddlewarePriority("session", "auth") makes session always run before auth. It should be
+// set before GlobalMiddlewareByName, Fallback and Static, they are registered to gin immediately.
+func (r *Route) MiddlewarePriority(names ...string) {
+	r.registry.MiddlewarePriority(names...)
+}
+
+// MiddlewareByName appends the middlewares resolved from the aliases or middleware groups, see Group.MiddlewareByName.
+func (r *Route) MiddlewareByName(names ...string) route.Router {
+	return r.Router.(*Group).MiddlewareByName(names...)
+}
+
+// GlobalMiddlewareByName registers the global middlewares resolved from the aliases or middleware groups,
+// they are sorted by MiddlewarePriority.
+func (r *Route) GlobalMiddlewareByName(names ...string) error {
+	middlewares, aliases, err := r.registry.resolveMiddlewares(names)
+	if err != nil {
+		return err
+	}
+
+	r.GlobalMiddleware(r.registry.sortMiddlewares(middlewares, aliases)...)
+
+	return nil
+}
+
+// BindModel registers the resolver of the route parameter, the resolved model can be got by RouteModel
+// in the handler, e.g. RouteModel[*models.User](ctx, "user").
+func (r *Route) BindModel(key string, resolver ModelResolver) {
+	r.registry.BindModel(key, resolver)
+}
+
+// RateLimiter registers a named rate limiter, it's used by Throttle(name) or the "throttle:name" middleware.
+func (r *Route) RateLimiter(name string, limiter RateLimiter) {
+	r.registry.RateLimiter(name, limiter)
+}
+
+// RateLimitStore sets the default store of the rate limits, e.g. NewCacheRateLimitStore(facades.Cache()).
+func (r *Route) RateLimitStore(store RateLimitStore) {
+	r.registry.RateLimitStore(store)
+}
+
+// Pattern constrains the route parameter with a regular expression in all routes,
+// it can be overridden by Action.Where.
+func (r *Route) Pattern(key, pattern string) {
+	r.registry.Pattern(key, pattern)
+}
+
+func (r *Route) Patterns(patterns map[string]string) {
+	for key, pattern := range patterns {
+		r.registry.Pattern(key, pattern)
+	}
+}
+
+// TimeoutResponse sets the response of the requests that time out in the Timeout middleware, it's 408 Request
+// Timeout without a body by default, e.g.
+//
+//	route.TimeoutResponse(func(ctx contractshttp.Context) contractshttp.Response {
+//		return ctx.Response().Json(http.StatusServiceUnavailable, contractshttp.Json{"message": "timeout"})
+//	})
+func (r *Route) TimeoutResponse(handler contractshttp.HandlerFunc) {
+	r.registry.mu.Lock()
+	defer r.registry.mu.Unlock()
+
+	r.registry.timeoutResponse = handler
+}
+
 func (r *Route) Recover(callback func(ctx contractshttp.Context, err any)) {
 	globalRecoverCallback = callback
 	r.setMiddlewares([]contractshttp.Middleware{
@@ -135,16 +314,17 @@ func (r *Route) Recover(callback func(ctx contractshttp.Context, err any)) {
 }
 
 func (r *Route) Listen(l net.Listener) error {
+	if err := r.flush(); err != nil {
+		return err
+	}
+
 	r.outputRoutes()
 	color.Green().Println("[HTTP] Listening on: " + str.Of(l.Addr().String()).Start("http://").String())
 
-	r.server = &http.Server{
-		Addr:           l.Addr().String(),
-		Handler:        http.AllowQuerySemicolons(r.instance),
-		MaxHeaderBytes: r.config.GetInt("http.drivers.ginx.header_limit", 4096) << 10,
-	}
+	server := r.newServer(l.Addr().String())
+	r.serving(server, false)
 
-	if err := r.server.Serve(l); !errors.Is(err, http.ErrServerClosed) {
+	if err := server.Serve(l); !errors.Is(err, http.ErrServerClosed) {
 		return err
 	}
 
@@ -156,16 +336,24 @@ func (r *Route) ListenTLS(l net.Listener) error {
 }
 
 func (r *Route) ListenTLSWithCert(l net.Listener, certFile, keyFile string) error {
+	if err := r.flush(); err != nil {
+		return err
+	}
+
 	r.outputRoutes()
 	color.Green().Println("[HTTPS] Listening on: " + str.Of(l.Addr().String()).Start("https://").String())
 
-	r.tlsServer = &http.Server{
-		Addr:           l.Addr().String(),
-		Handler:        http.AllowQuerySemicolons(r.instance),
-		MaxHeaderBytes: r.config.GetInt("http.drivers.ginx.header_limit", 4096) << 10,
+	server, loader, err := r.newTLSServer(l.Addr().String(), certFile, keyFile)
+	if err != nil {
+		return err
 	}
+	r.serving(server, true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go loader.Watch(ctx)
 
-	if err := r.tlsServer.ServeTLS(l, certFile, keyFile); !errors.Is(err, http.ErrServerClosed) {
+	if err := server.ServeTLS(l, "", ""); !errors.Is(err, http.ErrServerClosed) {
 		return err
 	}
 
@@ -173,15 +361,49 @@ func (r *Route) ListenTLSWithCert(l net.Listener, certFile, keyFile string) erro
 }
 
 func (r *Route) Info(name string) contractshttp.Info {
-	routes := r.GetRoutes()
+	info, _ := r.registry.Named(name)
 
-	for _, route := range routes {
-		if route.Name == name {
-			return route
+	return info
+}
+
+// Url generates the path of a named route, see RouteRegistry.Url.
+func (r *Route) Url(name string, params map[string]any, query map[string]any) (string, error) {
+	return r.registry.Url(name, params, query)
+}
+
+// SignedUrl generates the path of a named route with a signature query, the signature is verified by the
+// ValidateSignature middleware. The url expires at expiresAt if it is passed.
+func (r *Route) SignedUrl(name string, params map[string]any, expiresAt ...time.Time) (string, error) {
+	key := r.registry.appKey()
+	if key == "" {
+		return "", errors.New("app key can't be empty")
+	}
+
+	query := make(map[string]any)
+	if len(expiresAt) > 0 && !expiresAt[0].IsZero() {
+		query[signatureExpiresKey] = expiresAt[0].Unix()
+	}
+
+	url, err := r.registry.Url(name, params, query)
+	if err != nil {
+		return "", err
+	}
+
+	separator := "?"
+	if strings.Contains(url, "?") {
+		separator = "&"
+	}
+
+	// the signature of a domain route covers its host as well, so it can't be replayed on another domain
+	host, path := "", url
+	if strings.HasPrefix(url, "//") {
+		host, path = url[2:], ""
+		if index := strings.IndexAny(host, "/?"); index >= 0 {
+			host, path = host[:index], host[index:]
 		}
 	}
 
-	return contractshttp.Info{}
+	return url + separator + signatureKey + "=" + urlSignature(key, signaturePayload(host, path)), nil
 }
 
 func (r *Route) Run(host ...string) error {
@@ -195,16 +417,17 @@ func (r *Route) Run(host ...string) error {
 		host = append(host, completeHost)
 	}
 
+	if err := r.flush(); err != nil {
+		return err
+	}
+
 	r.outputRoutes()
 	color.Green().Println("[HTTP] Listening on: " + str.Of(host[0]).Start("http://").String())
 
-	r.server = &http.Server{
-		Addr:           host[0],
-		Handler:        http.AllowQuerySemicolons(r.instance),
-		MaxHeaderBytes: r.config.GetInt("http.drivers.ginx.header_limit", 4096) << 10,
-	}
+	server := r.newServer(host[0])
+	r.serving(server, false)
 
-	if err := r.server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
 		return err
 	}
 
@@ -236,39 +459,163 @@ func (r *Route) RunTLSWithCert(host, certFile, keyFile string) error {
 		return errors.New("certificate can't be empty")
 	}
 
+	if err := r.flush(); err != nil {
+		return err
+	}
+
 	r.outputRoutes()
 	color.Green().Println("[HTTPS] Listening on: " + str.Of(host).Start("https://").String())
 
-	r.tlsServer = &http.Server{
-		Addr:           host,
-		Handler:        http.AllowQuerySemicolons(r.instance),
-		MaxHeaderBytes: r.config.GetInt("http.drivers.ginx.header_limit", 4096) << 10,
+	server, loader, err := r.newTLSServer(host, certFile, keyFile)
+	if err != nil {
+		return err
 	}
+	r.serving(server, true)
 
-	if err := r.tlsServer.ListenAndServeTLS(certFile, keyFile); !errors.Is(err, http.ErrServerClosed) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go loader.Watch(ctx)
+
+	if err := server.ListenAndServeTLS("", ""); !errors.Is(err, http.ErrServerClosed) {
 		return err
 	}
 
 	return nil
 }
 
+// RunAll serves HTTP by the http.host and http.port config and HTTPS by the http.tls.* config at the same time.
+// The HTTP requests are redirected to HTTPS, and the HTTPS responses have the Strict-Transport-Security header
+// whose max-age is the http.drivers.ginx.hsts_max_age config in seconds. When a server fails, the other one is
+// shut down as well, and Shutdown shuts down both of them.
+func (r *Route) RunAll() error {
+	port := r.config.GetString("http.port")
+	tlsPort := r.config.GetString("http.tls.port")
+	if port == "" || tlsPort == "" {
+		return errors.New("port can't be empty")
+	}
+
+	certFile := r.config.GetString("http.tls.ssl.cert")
+	keyFile := r.config.GetString("http.tls.ssl.key")
+	if certFile == "" || keyFile == "" {
+		return errors.New("certificate can't be empty")
+	}
+
+	host := r.config.GetString("http.host") + ":" + port
+	tlsHost := r.config.GetString("http.tls.host") + ":" + tlsPort
+
+	if err := r.flush(); err != nil {
+		return err
+	}
+
+	r.outputRoutes()
+	color.Green().Println("[HTTP] Listening on: " + str.Of(host).Start("http://").String())
+	color.Green().Println("[HTTPS] Listening on: " + str.Of(tlsHost).Start("https://").String())
+
+	tlsServer, loader, err := r.newTLSServer(tlsHost, certFile, keyFile)
+	if err != nil {
+		return err
+	}
+	tlsServer.Handler = hstsHandler(tlsServer.Handler, r.config.GetInt("http.drivers.ginx.hsts_max_age", 31536000))
+	server := r.newServer(host)
+	server.Handler = httpsRedirectHandler(tlsPort)
+
+	r.serving(server, false)
+	r.serving(tlsServer, true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go loader.Watch(ctx)
+
+	errs := make(chan error, 2)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+	go func() {
+		errs <- tlsServer.ListenAndServeTLS("", "")
+	}()
+
+	var result []error
+	for range 2 {
+		if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+			result = append(result, err)
+			_ = r.Shutdown()
+		}
+	}
+
+	return errors.Join(result...)
+}
+
 func (r *Route) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
-	r.instance.ServeHTTP(writer, request)
+	if isDomainPath(request) {
+		http.NotFound(writer, request)
+		return
+	}
+
+	// the routes that fail to be registered are reported once, the others are served as usual
+	if err := r.flush(); err != nil && LogFacade != nil {
+		LogFacade.Error(err)
+	}
+
+	r.registry.serve(r.instance, writer, r.registry.matchDomain(request))
+}
+
+// flush registers the pending routes to gin, it's called before the Route starts serving, so the conflicts of
+// the routes fail Run and Listen. The routes added while serving are registered once the requests being routed
+// are routed, the route trees of gin can't be changed while they're being read.
+func (r *Route) flush() error {
+	return r.registry.flush()
+}
+
+// Ready reports whether the route is serving requests, it turns false as soon as Shutdown is called, so the
+// readiness probes stop sending traffic to the application while the requests are drained.
+func (r *Route) Ready() bool {
+	return r.ready.Load()
+}
+
+// InFlight returns the number of the requests being handled, the requests that time out are counted until their
+// handlers return.
+func (r *Route) InFlight() int64 {
+	return r.registry.inFlight.Load()
 }
 
+// Shutdown gracefully shuts down the HTTP and HTTPS servers at the same time. The route turns not ready first
+// and the running streams are canceled, then it waits for the in-flight requests and the AfterResponse
+// callbacks until ctx is done, the errors of the servers and the remaining requests are joined.
 func (r *Route) Shutdown(ctx ...context.Context) error {
 	c := context.Background()
 	if len(ctx) > 0 {
 		c = ctx[0]
 	}
 
-	if r.server != nil {
-		return r.server.Shutdown(c)
-	}
-	if r.tlsServer != nil {
-		return r.tlsServer.Shutdown(c)
+	r.ready.Store(false)
+	r.registry.cancelStreams()
+
+	r.mu.Lock()
+	names := []string{"HTTP", "HTTPS"}
+	servers := []*http.Server{r.server, r.tlsServer}
+	r.mu.Unlock()
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(servers)+1)
+	for i, server := range servers {
+		if server == nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if err := server.Shutdown(c); err != nil {
+				errs[i] = fmt.Errorf("shutdown %s server: %w", names[i], err)
+			}
+		}()
 	}
-	return nil
+	wg.Wait()
+
+	errs[len(servers)] = r.drain(c)
+
+	return errors.Join(errs...)
 }
 
 func (r *Route) Test(request *http.Request) (*http.Response, error) {
@@ -279,6 +626,263 @@ func (r *Route) Test(request *http.Request) (*http.Response, error) {
 	return recorder.Result(), nil
 }
 
+// Certificate adds a TLS certificate that's served when the SNI of the client matches its DNS names, the
+// certificate of the http.tls.ssl config or the one passed to RunTLSWithCert and ListenTLSWithCert is served
+// by default. The certificates are reloaded when their files change or the process receives SIGHUP.
+func (r *Route) Certificate(certFile, keyFile string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.certificates = append(r.certificates, certificateFiles{certFile: certFile, keyFile: keyFile})
+}
+
+// newTLSServer creates the server whose certificates are served by a CertificateLoader, the loader should
+// watch the files while the server is running.
+func (r *Route) newTLSServer(addr, certFile, keyFile string) (*http.Server, *CertificateLoader, error) {
+	clientAuth, clientCAs, err := r.clientAuth()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	loader, err := NewCertificateLoader(certFile, keyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.mu.Lock()
+	certificates := slices.Clone(r.certificates)
+	r.mu.Unlock()
+
+	for _, certificate := range certificates {
+		if err := loader.Add(certificate.certFile, certificate.keyFile); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	server := r.newServer(addr)
+	server.TLSConfig = &tls.Config{
+		GetCertificate: loader.GetCertificate,
+		ClientAuth:     clientAuth,
+		ClientCAs:      clientCAs,
+	}
+
+	return server, loader, nil
+}
+
+// clientAuth returns the client authentication of mutual TLS by the http.drivers.ginx.client_auth and
+// http.drivers.ginx.client_ca config, the CA is required when the client certificates are verified, otherwise
+// any certificate signed by the system roots would be accepted.
+func (r *Route) clientAuth() (tls.ClientAuthType, *x509.CertPool, error) {
+	var clientAuth tls.ClientAuthType
+	switch mode := r.config.GetString("http.drivers.ginx.client_auth"); mode {
+	case "", "none":
+		clientAuth = tls.NoClientCert
+	case "request":
+		clientAuth = tls.RequestClientCert
+	case "require":
+		clientAuth = tls.RequireAndVerifyClientCert
+	case "verify_if_given":
+		clientAuth = tls.VerifyClientCertIfGiven
+	default:
+		return 0, nil, fmt.Errorf("invalid client auth %s, it should be none, request, require or verify_if_given", mode)
+	}
+
+	caFile := r.config.GetString("http.drivers.ginx.client_ca")
+	if caFile == "" {
+		if clientAuth == tls.RequireAndVerifyClientCert || clientAuth == tls.VerifyClientCertIfGiven {
+			return 0, nil, errors.New("the client CA is required to verify the client certificates, please set http.drivers.ginx.client_ca")
+		}
+
+		return clientAuth, nil, nil
+	}
+
+	ca, err := os.ReadFile(caFile)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read the client CA %s: %w", caFile, err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(ca) {
+		return 0, nil, fmt.Errorf("no certificate is found in the client CA %s", caFile)
+	}
+
+	return clientAuth, clientCAs, nil
+}
+
+// serving stores the server to be shut down by Shutdown and marks the route ready.
+func (r *Route) serving(server *http.Server, tls bool) {
+	r.mu.Lock()
+	if tls {
+		r.tlsServer = server
+	} else {
+		r.server = server
+	}
+	r.mu.Unlock()
+
+	r.registry.resumeStreams()
+	r.ready.Store(true)
+}
+
+// drain waits for the in-flight requests and the AfterResponse callbacks, the servers don't track the hijacked
+// connections, the requests that are passed to ServeHTTP directly and the handlers of the timed out requests.
+func (r *Route) drain(ctx context.Context) error {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for r.registry.inFlight.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%d requests are still in flight: %w", r.registry.inFlight.Load(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		r.registry.afterResponses.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("after response callbacks are still running: %w", ctx.Err())
+	}
+}
+
+// httpsRedirectHandler redirects the requests to the same host and path on the HTTPS port.
+func httpsRedirectHandler(tlsPort string) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		host := request.Host
+		if hostname, _, err := net.SplitHostPort(host); err == nil {
+			host = hostname
+		}
+		if tlsPort != "443" {
+			host = net.JoinHostPort(host, tlsPort)
+		}
+
+		// 308 keeps the method and the body of the requests that aren't GET or HEAD
+		code := http.StatusPermanentRedirect
+		if request.Method == http.MethodGet || request.Method == http.MethodHead {
+			code = http.StatusMovedPermanently
+		}
+
+		http.Redirect(writer, request, "https://"+host+request.URL.RequestURI(), code)
+	})
+}
+
+// hstsHandler sets the Strict-Transport-Security header of the responses, it's skipped when maxAge is 0.
+func hstsHandler(handler http.Handler, maxAge int) http.Handler {
+	if maxAge <= 0 {
+		return handler
+	}
+
+	value := "max-age=" + strconv.Itoa(maxAge)
+
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Strict-Transport-Security", value)
+		handler.ServeHTTP(writer, request)
+	})
+}
+
+// newServer creates the server of Run, RunTLSWithCert, Listen and ListenTLSWithCert by the http.drivers.ginx.*
+// config, the timeouts are in seconds and 0 means no timeout. The write timeout covers the whole response, so
+// it should be longer than the streaming responses, or they are cut off.
+func (r *Route) newServer(addr string) *http.Server {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           http.AllowQuerySemicolons(r),
+		MaxHeaderBytes:    r.config.GetInt("http.drivers.ginx.header_limit", 4096) << 10,
+		ReadHeaderTimeout: time.Duration(r.config.GetInt("http.drivers.ginx.read_header_timeout", 10)) * time.Second,
+		ReadTimeout:       time.Duration(r.config.GetInt("http.drivers.ginx.read_timeout", 0)) * time.Second,
+		WriteTimeout:      time.Duration(r.config.GetInt("http.drivers.ginx.write_timeout", 0)) * time.Second,
+		IdleTimeout:       time.Duration(r.config.GetInt("http.drivers.ginx.idle_timeout", 120)) * time.Second,
+	}
+
+	if connState, ok := r.config.Get("http.drivers.ginx.conn_state").(func(net.Conn, http.ConnState)); ok {
+		server.ConnState = connState
+	}
+	server.SetKeepAlivesEnabled(r.config.GetBool("http.drivers.ginx.keep_alive", true))
+
+	return server
+}
+
+// noMethodHandler handles the requests whose path exists but method doesn't, gin has set the Allow header
+// and the 405 status before calling it.
+func noMethodHandler(registry *RouteRegistry, methodNotAllowed, autoOptions bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allow := c.Writer.Header().Get("Allow")
+		if autoOptions {
+			// the routes registered by Options already list it
+			if !slices.Contains(strings.Split(allow, ", "), http.MethodOptions) {
+				allow += ", " + http.MethodOptions
+				c.Header("Allow", allow)
+			}
+
+			if c.Request.Method == http.MethodOptions {
+				c.AbortWithStatus(http.StatusNoContent)
+				return
+			}
+		}
+
+		if !methodNotAllowed {
+			c.Writer.Header().Del("Allow")
+			registry.notFound(c)
+		}
+	}
+}
+
+// bodyLimitMiddleware limits the size of the request body by the body limit of the matched route or the global
+// one, the requests whose Content-Length exceeds the limit get 413 Payload Too Large before they are handled.
+func bodyLimitMiddleware(registry *RouteRegistry, bodyLimit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := bodyLimit
+		if route := registry.matchedRoute(c); route != nil && route.action.bodyLimit != nil {
+			limit = *route.action.bodyLimit
+		}
+
+		if limit <= 0 || c.Request.Body == nil || c.Request.Body == http.NoBody {
+			return
+		}
+
+		if c.Request.ContentLength > limit {
+			if LogFacade != nil {
+				LogFacade.Warningf("request body of %s %s exceeds the limit of %d bytes", c.Request.Method, c.Request.URL.Path, limit)
+			}
+			c.AbortWithStatus(http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		c.Request.Body = &limitedBody{ReadCloser: http.MaxBytesReader(c.Writer, c.Request.Body, limit), instance: c}
+	}
+}
+
+// limitedBody responds 413 Payload Too Large once the body exceeds the limit while it's read, so the limit
+// applies to the bodies read from Origin() as well, not only to the ones parsed by the request.
+type limitedBody struct {
+	io.ReadCloser
+	instance *gin.Context
+	once     sync.Once
+}
+
+func (r *limitedBody) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+
+	var maxBytesError *http.MaxBytesError
+	if err != nil && errors.As(err, &maxBytesError) {
+		r.once.Do(func() {
+			request := r.instance.Request
+			if LogFacade != nil {
+				LogFacade.Warningf("request body of %s %s exceeds the limit of %d bytes", request.Method, request.URL.Path, maxBytesError.Limit)
+			}
+			r.instance.AbortWithStatus(http.StatusRequestEntityTooLarge)
+		})
+	}
+
+	return n, err
+}
+
 func (r *Route) outputRoutes() {
 	if r.config.GetBool("app.debug") && support.RuntimeMode != support.RuntimeArtisan && support.RuntimeMode != support.RuntimeTest {
 		if err := App.MakeArtisan().Call("route:list"); err != nil {
@@ -288,10 +892,14 @@ func (r *Route) outputRoutes() {
 }
 
 func (r *Route) setMiddlewares(middlewares []contractshttp.Middleware) {
+	if slices.ContainsFunc(middlewares, isTimeoutMiddleware) {
+		r.registry.globalTimeout.Store(true)
+	}
 	r.instance.Use(middlewaresToGinHandlers(middlewares)...)
 	r.Router = NewGroup(
 		r.config,
 		r.instance.Group("/"),
+		r.registry,
 		"",
 		[]contractshttp.Middleware{},
 		[]contractshttp.Middleware{ResponseMiddleware()},
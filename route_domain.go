@@ -0,0 +1,184 @@
+package gin
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// domainPathPrefix is the prefix of the paths registered to gin for a domain, gin can't route by host,
+// so the request path is moved under the prefix of the matched domain before gin handles it.
+const domainPathPrefix = "/__domain"
+
+var domainPrefixRegex = regexp.MustCompile(`^` + domainPathPrefix + `\d+`)
+
+type routeDomain struct {
+	pattern string
+	host    *regexp.Regexp
+	prefix  string
+	paths   []*regexp.Regexp
+}
+
+type domainMatch struct {
+	path    string
+	rawPath string
+	params  gin.Params
+}
+
+type domainMatchKey struct{}
+
+func newRouteDomain(pattern string, index int) *routeDomain {
+	var host strings.Builder
+	last := 0
+	for _, match := range routeParameterRegex.FindAllStringSubmatchIndex(pattern, -1) {
+		host.WriteString(regexp.QuoteMeta(pattern[last:match[0]]))
+		host.WriteString("(?P<" + pattern[match[2]:match[3]] + ">[^.]+)")
+		last = match[1]
+	}
+	host.WriteString(regexp.QuoteMeta(pattern[last:]))
+
+	return &routeDomain{
+		pattern: pattern,
+		host:    regexp.MustCompile(`(?i)^` + host.String() + `$`),
+		prefix:  domainPathPrefix + strconv.Itoa(index),
+	}
+}
+
+// addDomainPath records a gin path of the domain and returns the path that should be registered to gin.
+func (r *RouteRegistry) addDomainPath(pattern, ginPath string, static bool) string {
+	domain := r.domain(pattern)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	domain.paths = append(domain.paths, ginPathRegex(ginPath, static))
+
+	return domain.prefix + ginPath
+}
+
+// domain returns the domain of the pattern, it's created if it doesn't exist.
+func (r *RouteRegistry) domain(pattern string) *routeDomain {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, domain := range r.domains {
+		if domain.pattern == pattern {
+			return domain
+		}
+	}
+
+	domain := newRouteDomain(pattern, len(r.domains))
+	r.domains = append(r.domains, domain)
+
+	return domain
+}
+
+// matchDomain moves the request path under the prefix of the first domain that matches both the host
+// and the path, the requests that don't match any domain are handled by the routes without a domain.
+func (r *RouteRegistry) matchDomain(request *http.Request) *http.Request {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.domains) == 0 {
+		return request
+	}
+
+	host := request.Host
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+
+	for _, domain := range r.domains {
+		matches := domain.host.FindStringSubmatch(host)
+		if matches == nil || !domain.hasPath(request.URL.Path) {
+			continue
+		}
+
+		match := &domainMatch{
+			path:    request.URL.Path,
+			rawPath: request.URL.RawPath,
+		}
+		for i, key := range domain.host.SubexpNames() {
+			if key != "" {
+				match.params = append(match.params, gin.Param{Key: key, Value: matches[i]})
+			}
+		}
+
+		request = request.WithContext(context.WithValue(request.Context(), domainMatchKey{}, match))
+		url := *request.URL
+		url.Path = domain.prefix + url.Path
+		if url.RawPath != "" {
+			url.RawPath = domain.prefix + url.RawPath
+		}
+		request.URL = &url
+
+		return request
+	}
+
+	return request
+}
+
+// isDomainPath reports whether the request path is under the prefix of the domain routes, the prefix is reserved,
+// or the clients could request the domain routes directly with any host.
+func isDomainPath(request *http.Request) bool {
+	return strings.HasPrefix(path.Clean("/"+request.URL.Path), domainPathPrefix)
+}
+
+func (r *routeDomain) hasPath(path string) bool {
+	for _, regex := range r.paths {
+		if regex.MatchString(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// restoreDomainMatch restores the request path moved by matchDomain and adds the host parameters,
+// it runs before any other middleware, so they only see the original request.
+func restoreDomainMatch(c *gin.Context) {
+	match, ok := c.Request.Context().Value(domainMatchKey{}).(*domainMatch)
+	if !ok {
+		return
+	}
+
+	c.Request.URL.Path = match.path
+	c.Request.URL.RawPath = match.rawPath
+	for _, param := range match.params {
+		if _, exist := c.Params.Get(param.Key); !exist {
+			c.Params = append(c.Params, param)
+		}
+	}
+}
+
+// ginPathRegex converts a gin path to a regular expression, static paths also match the files under them.
+func ginPathRegex(ginPath string, static bool) *regexp.Regexp {
+	segments := strings.Split(ginPath, "/")
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			segments[i] = "[^/]+"
+		case strings.HasPrefix(segment, "*"):
+			segments[i] = ".*"
+		default:
+			segments[i] = regexp.QuoteMeta(segment)
+		}
+	}
+
+	pattern := strings.Join(segments, "/")
+	if static {
+		pattern = strings.TrimSuffix(pattern, "/") + "(/.*)?"
+	}
+
+	return regexp.MustCompile("^" + pattern + "$")
+}
+
+func trimDomainPrefix(path string) string {
+	return domainPrefixRegex.ReplaceAllString(path, "")
+}
@@ -0,0 +1,139 @@
+package gin
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"regexp"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// routeFallback handles the unmatched requests under a prefix. Its handlers are the NoRoute handlers of
+// a dedicated engine, so the group middlewares can call Next like they do in a matched route.
+type routeFallback struct {
+	domain string
+	host   *regexp.Regexp
+	prefix string
+	path   *regexp.Regexp
+	engine *gin.Engine
+}
+
+// fallbackState keeps the state of the request that gin resets when the fallback engine handles it.
+type fallbackState struct {
+	keys   map[any]any
+	writer gin.ResponseWriter
+	params gin.Params
+}
+
+type fallbackStateKey struct{}
+
+func newRouteFallback(domain string, host *regexp.Regexp, prefix string, handlers []gin.HandlerFunc) *routeFallback {
+	engine := gin.New()
+	engine.Use(restoreFallbackState)
+	engine.NoRoute(handlers...)
+
+	return &routeFallback{
+		domain: domain,
+		host:   host,
+		prefix: prefix,
+		path:   ginPathRegex(pathToGinPath(prefix), true),
+		engine: engine,
+	}
+}
+
+func (r *routeFallback) handle(c *gin.Context) {
+	state := &fallbackState{
+		keys:   c.Keys,
+		writer: c.Writer,
+		params: slices.Clone(c.Params),
+	}
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), fallbackStateKey{}, state))
+
+	r.engine.HandleContext(c)
+}
+
+func (r *routeFallback) match(host, path string) bool {
+	if r.host != nil && !r.host.MatchString(host) {
+		return false
+	}
+
+	return r.path.MatchString(path)
+}
+
+// setFallback sets the fallback of the prefix, it replaces the previous one of the same domain and prefix.
+func (r *RouteRegistry) setFallback(domain, prefix string, handlers []gin.HandlerFunc) {
+	var host *regexp.Regexp
+	if domain != "" {
+		host = r.domain(domain).host
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fallback := newRouteFallback(domain, host, prefix, handlers)
+	for i, item := range r.fallbacks {
+		if item.domain == domain && item.prefix == prefix {
+			r.fallbacks[i] = fallback
+			return
+		}
+	}
+
+	r.fallbacks = append(r.fallbacks, fallback)
+}
+
+// matchFallback returns the fallback of the longest prefix that matches the request, the fallbacks of
+// a domain win over the ones without a domain of the same prefix.
+func (r *RouteRegistry) matchFallback(c *gin.Context) *routeFallback {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	host := c.Request.Host
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+
+	var matched *routeFallback
+	for _, fallback := range r.fallbacks {
+		if !fallback.match(host, c.Request.URL.Path) {
+			continue
+		}
+		if matched == nil || len(fallback.prefix) > len(matched.prefix) ||
+			(len(fallback.prefix) == len(matched.prefix) && fallback.domain != "") {
+			matched = fallback
+		}
+	}
+
+	return matched
+}
+
+// noRoute handles the unmatched requests by the matched fallback, gin responds 404 if there is none.
+func (r *RouteRegistry) noRoute(c *gin.Context) {
+	if fallback := r.matchFallback(c); fallback != nil {
+		fallback.handle(c)
+	}
+}
+
+// notFound handles the request by the matched fallback, or responds 404 if there is none.
+func (r *RouteRegistry) notFound(c *gin.Context) {
+	if fallback := r.matchFallback(c); fallback != nil {
+		fallback.handle(c)
+		c.Abort()
+
+		return
+	}
+
+	c.AbortWithStatus(http.StatusNotFound)
+}
+
+func restoreFallbackState(c *gin.Context) {
+	state, ok := c.Request.Context().Value(fallbackStateKey{}).(*fallbackState)
+	if !ok {
+		return
+	}
+
+	c.Keys = state.keys
+	c.Writer = state.writer
+	c.Params = state.params
+}
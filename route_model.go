@@ -0,0 +1,94 @@
+package gin
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	contractshttp "github.com/goravel/framework/contracts/http"
+	frameworkerrors "github.com/goravel/framework/errors"
+)
+
+// ModelResolver resolves the model of a route parameter, it should return a nil model or
+// errors.OrmRecordNotFound when the model doesn't exist, the request is handled as not found then.
+type ModelResolver func(ctx contractshttp.Context, value string) (any, error)
+
+type routeModelKey string
+
+// RouteModel returns the model resolved for the route parameter, see Route.BindModel.
+func RouteModel[T any](ctx contractshttp.Context, key string) T {
+	model, _ := ctx.Value(routeModelKey(key)).(T)
+
+	return model
+}
+
+// BindModel registers the resolver of the route parameter, the model is resolved before the handler
+// of every route that contains the {key} parameter.
+func (r *RouteRegistry) BindModel(key string, resolver ModelResolver) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.resolvers[key] = resolver
+}
+
+// modelResolvers returns the resolvers of the parameters in the path, in the order of the parameters.
+func (r *RouteRegistry) modelResolvers(path string) ([]string, []ModelResolver) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var (
+		keys      []string
+		resolvers []ModelResolver
+	)
+	for _, match := range routeParameterRegex.FindAllStringSubmatch(path, -1) {
+		key := strings.TrimSuffix(match[1], "?")
+		if resolver, exist := r.resolvers[key]; exist {
+			keys = append(keys, key)
+			resolvers = append(resolvers, resolver)
+		}
+	}
+
+	return keys, resolvers
+}
+
+// bindModels resolves the models of the route parameters, the absent optional parameters are skipped.
+func bindModels(registry *RouteRegistry, keys []string, resolvers []ModelResolver) contractshttp.Middleware {
+	return func(ctx contractshttp.Context) {
+		for i, key := range keys {
+			value := ctx.Request().Route(key)
+			if value == "" {
+				continue
+			}
+
+			model, err := resolvers[i](ctx, value)
+			if err != nil && !errors.Is(err, frameworkerrors.OrmRecordNotFound) {
+				if LogFacade != nil {
+					LogFacade.WithContext(ctx).Request(ctx.Request()).Error(err)
+				}
+				ctx.Request().Abort(contractshttp.StatusInternalServerError)
+				return
+			}
+			if err != nil || isNil(model) {
+				registry.notFound(ctx.(*Context).Instance())
+				return
+			}
+
+			ctx.WithValue(routeModelKey(key), model)
+		}
+
+		ctx.Request().Next()
+	}
+}
+
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice:
+		return reflect.ValueOf(value).IsNil()
+	}
+
+	return false
+}
@@ -0,0 +1,483 @@
+package gin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goravel/framework/contracts/config"
+	contractshttp "github.com/goravel/framework/contracts/http"
+	"github.com/spf13/cast"
+)
+
+const routeRegistryKey = "goravel_routeRegistry"
+
+var routeParameterRegex = regexp.MustCompile(`{(.*?)}`)
+
+var routeMethods = []string{contractshttp.MethodGet + "|" + contractshttp.MethodHead, contractshttp.MethodHead, contractshttp.MethodGet, contractshttp.MethodPost, contractshttp.MethodPut + "|" + contractshttp.MethodPatch, contractshttp.MethodPut, contractshttp.MethodDelete, contractshttp.MethodPatch, contractshttp.MethodOptions, contractshttp.MethodAny, contractshttp.MethodResource, contractshttp.MethodStatic, contractshttp.MethodStaticFile, contractshttp.MethodStaticFS}
+
+// RouteRegistry stores the information of the routes registered on a Route and its groups.
+// It's safe for concurrent use, so routes can be read while the engine is serving requests.
+type RouteRegistry struct {
+	mu sync.RWMutex
+	// map[path]map[method]info
+	routes      map[string]map[string]contractshttp.Info
+	patterns    map[string]*regexp.Regexp
+	domains     []*routeDomain
+	fallbacks   []*routeFallback
+	resolvers   map[string]ModelResolver
+	middlewares *middlewareAliases
+	limiters    map[string]RateLimiter
+	limitStore  RateLimitStore
+	// the config of the Route, the urls are signed and validated by its app.key
+	config config.Config
+	// the response of the requests that time out in the Timeout middleware
+	timeoutResponse contractshttp.HandlerFunc
+	// whether the Timeout middleware of http.request_timeout or the ones given to GlobalMiddleware are installed
+	requestTimeout atomic.Bool
+	globalTimeout  atomic.Bool
+
+	// map[path]map[method]action
+	actions map[string]map[string]*Action
+	// map[method + " " + gin path]route, it finds the route in the global middlewares
+	ginRoutes map[string]*actionRoute
+
+	pendingMu  sync.Mutex
+	pending    []*Action
+	hasPending atomic.Bool
+	// guards the route trees of gin, the requests hold it until they are routed, see serve
+	ginMu sync.RWMutex
+
+	// the running callbacks registered by AfterResponse
+	afterResponses sync.WaitGroup
+	// the requests being handled, see timeoutMiddleware
+	inFlight atomic.Int64
+
+	// the streams are canceled when the Route shuts down
+	streamsMu   sync.Mutex
+	streams     context.Context
+	stopStreams context.CancelFunc
+}
+
+func NewRouteRegistry() *RouteRegistry {
+	streams, stopStreams := context.WithCancel(context.Background())
+
+	return &RouteRegistry{
+		streams:     streams,
+		stopStreams: stopStreams,
+		routes:      make(map[string]map[string]contractshttp.Info),
+		patterns:    make(map[string]*regexp.Regexp),
+		actions:     make(map[string]map[string]*Action),
+		ginRoutes:   make(map[string]*actionRoute),
+		resolvers:   make(map[string]ModelResolver),
+		middlewares: newMiddlewareAliases(),
+		limiters:    make(map[string]RateLimiter),
+		limitStore:  NewMemoryRateLimitStore(),
+	}
+}
+
+func (r *RouteRegistry) Add(info contractshttp.Info) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.routes[info.Path]; !ok {
+		r.routes[info.Path] = make(map[string]contractshttp.Info)
+	}
+
+	r.routes[info.Path][info.Method] = info
+}
+
+func (r *RouteRegistry) Get(path, method string) (contractshttp.Info, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	info, ok := r.routes[path][method]
+
+	return info, ok
+}
+
+func (r *RouteRegistry) SetName(path, method, name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	info, ok := r.routes[path][method]
+	if !ok {
+		return
+	}
+
+	info.Name = name
+	r.routes[path][method] = info
+}
+
+// All returns the registered routes, sorted by path and then by method.
+func (r *RouteRegistry) All() []contractshttp.Info {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	paths := []string{}
+	for path := range r.routes {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	var infos []contractshttp.Info
+	for _, path := range paths {
+		for _, method := range routeMethods {
+			if info, ok := r.routes[path][method]; ok {
+				infos = append(infos, info)
+			}
+		}
+	}
+
+	return infos
+}
+
+func (r *RouteRegistry) Has(path string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, exist := r.routes[path]
+
+	return exist
+}
+
+// Match returns the route information of a path for the given request method.
+func (r *RouteRegistry) Match(path, method string) (contractshttp.Info, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	methodToInfo, exist := r.routes[path]
+	if !exist {
+		return contractshttp.Info{}, false
+	}
+
+	if info, exist := methodToInfo[method]; exist {
+		return info, true
+	}
+
+	// the methods registered together, e.g. GET|HEAD, PUT|PATCH
+	for infoMethod, info := range methodToInfo {
+		if slices.Contains(strings.Split(infoMethod, "|"), method) {
+			return info, true
+		}
+	}
+
+	for _, tryMethod := range []string{contractshttp.MethodAny, contractshttp.MethodResource} {
+		if info, exist := methodToInfo[tryMethod]; exist {
+			return info, true
+		}
+	}
+
+	return contractshttp.Info{}, false
+}
+
+func (r *RouteRegistry) Named(name string) (contractshttp.Info, bool) {
+	for _, info := range r.All() {
+		if info.Name == name {
+			return info, true
+		}
+	}
+
+	return contractshttp.Info{}, false
+}
+
+// Pattern constrains the parameter with a regular expression in all routes.
+func (r *RouteRegistry) Pattern(key, pattern string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.patterns[key] = compilePattern(pattern)
+}
+
+func (r *RouteRegistry) Patterns() map[string]*regexp.Regexp {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	patterns := make(map[string]*regexp.Regexp, len(r.patterns))
+	for key, pattern := range r.patterns {
+		patterns[key] = pattern
+	}
+
+	return patterns
+}
+
+func (r *RouteRegistry) action(path, method string) (*Action, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	action, exist := r.actions[path][method]
+
+	return action, exist
+}
+
+func (r *RouteRegistry) addAction(path, method string, action *Action) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.actions[path]; !ok {
+		r.actions[path] = make(map[string]*Action)
+	}
+
+	r.actions[path][method] = action
+}
+
+// remove removes the route information and the action of the path and method.
+func (r *RouteRegistry) remove(path, method string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.routes[path], method)
+	if len(r.routes[path]) == 0 {
+		delete(r.routes, path)
+	}
+
+	delete(r.actions[path], method)
+	if len(r.actions[path]) == 0 {
+		delete(r.actions, path)
+	}
+}
+
+func (r *RouteRegistry) addGinRoute(method, ginPath string, route *actionRoute) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.ginRoutes[method+" "+ginPath] = route
+}
+
+// hasTimeout reports whether a Timeout middleware applies to the request routed by gin.
+func (r *RouteRegistry) hasTimeout(c *gin.Context) bool {
+	if r.globalTimeout.Load() {
+		return true
+	}
+
+	route := r.matchedRoute(c)
+	if route == nil {
+		return r.requestTimeout.Load()
+	}
+
+	// the timeout of http.request_timeout is skipped for the routes that set their own timeouts
+	return route.timeout || r.requestTimeout.Load() && route.action.timeout == nil
+}
+
+// matchedRoute returns the route that matches the request, the global middlewares run before the route
+// is set to the context, so it's found by the path that gin matches.
+func (r *RouteRegistry) matchedRoute(c *gin.Context) *actionRoute {
+	if route, exist := c.Get(routeActionKey); exist {
+		return route.(*actionRoute)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if route, exist := r.ginRoutes[c.Request.Method+" "+c.FullPath()]; exist {
+		return route
+	}
+
+	return r.ginRoutes[contractshttp.MethodAny+" "+c.FullPath()]
+}
+
+func (r *RouteRegistry) addPending(action *Action) {
+	r.pendingMu.Lock()
+	defer r.pendingMu.Unlock()
+
+	r.pending = append(r.pending, action)
+	r.hasPending.Store(true)
+}
+
+// flush registers the pending actions to gin, the conflicts of the routes are returned as errors, and the pending
+// actions are cleared even if some of them fail, so the failures are only reported once.
+func (r *RouteRegistry) flush() error {
+	if !r.hasPending.Load() {
+		return nil
+	}
+
+	r.pendingMu.Lock()
+	defer r.pendingMu.Unlock()
+
+	r.ginMu.Lock()
+	defer r.ginMu.Unlock()
+
+	pending := r.pending
+	r.pending = nil
+	r.hasPending.Store(false)
+
+	var errs []error
+	for _, action := range pending {
+		if err := action.register(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Url generates the path of a named route, the {key} placeholders are filled by params,
+// params that don't match any placeholder are appended to the query string together with query.
+func (r *RouteRegistry) Url(name string, params map[string]any, query map[string]any) (string, error) {
+	info, exist := r.Named(name)
+	if !exist {
+		return "", fmt.Errorf("route %s is not defined", name)
+	}
+
+	used := make(map[string]bool)
+	var missing []string
+	var segments []string
+	for _, segment := range strings.Split(info.Path, "/") {
+		optionalMissing := false
+		segment = routeParameterRegex.ReplaceAllStringFunc(segment, func(placeholder string) string {
+			key := placeholder[1 : len(placeholder)-1]
+			optional := strings.HasSuffix(key, "?")
+			key = strings.TrimSuffix(key, "?")
+
+			value, exist := params[key]
+			if !exist {
+				if optional {
+					optionalMissing = true
+				} else {
+					missing = append(missing, key)
+				}
+
+				return placeholder
+			}
+
+			used[key] = true
+
+			return url.PathEscape(cast.ToString(value))
+		})
+
+		if !optionalMissing {
+			segments = append(segments, segment)
+		}
+	}
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing parameters %v for route %s", missing, name)
+	}
+
+	path := strings.Join(segments, "/")
+	if path == "" {
+		path = "/"
+	}
+	if !strings.HasPrefix(path, "/") {
+		// the path of a domain route starts with the domain, e.g. //tenant.example.com/users
+		path = "//" + path
+	}
+
+	values := url.Values{}
+	for key, value := range params {
+		if !used[key] {
+			values.Set(key, cast.ToString(value))
+		}
+	}
+	for key, value := range query {
+		values.Set(key, cast.ToString(value))
+	}
+
+	if len(values) > 0 {
+		path += "?" + values.Encode()
+	}
+
+	return path, nil
+}
+
+// serve serves the request by gin. The route trees are locked until the request is routed rather than until it
+// finishes, so the routes added while serving don't wait for the long-running requests, e.g. the streams.
+func (r *RouteRegistry) serve(engine *gin.Engine, writer http.ResponseWriter, request *http.Request) {
+	lock := &routingLock{mu: &r.ginMu}
+	r.ginMu.RLock()
+	// the handlers don't run if gin redirects the request, e.g. for the trailing slash
+	defer lock.unlock()
+
+	engine.ServeHTTP(writer, request.WithContext(context.WithValue(request.Context(), routingLockKey{}, lock)))
+}
+
+type routingLockKey struct{}
+
+// routingLock is the read lock of the route trees held by a request being routed.
+type routingLock struct {
+	mu   *sync.RWMutex
+	once sync.Once
+}
+
+func (l *routingLock) unlock() {
+	l.once.Do(l.mu.RUnlock)
+}
+
+// unlockRouting releases the lock of the route trees once the request is routed, it's called by the first
+// handlers of the request.
+func unlockRouting(c *gin.Context) {
+	if lock, ok := c.Request.Context().Value(routingLockKey{}).(*routingLock); ok {
+		lock.unlock()
+	}
+}
+
+func routeRegistryMiddleware(registry *RouteRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		unlockRouting(c)
+		restoreDomainMatch(c)
+		c.Set(routeRegistryKey, registry)
+	}
+}
+
+// appKey returns the key that signs the urls, it's empty if the registry doesn't belong to a Route.
+func (r *RouteRegistry) appKey() string {
+	if r == nil || r.config == nil {
+		return ""
+	}
+
+	return r.config.GetString("app.key")
+}
+
+// contextRouteRegistry returns the registry of the Route that serves the request, it's nil for the other contexts.
+func contextRouteRegistry(ctx contractshttp.Context) *RouteRegistry {
+	if context, ok := ctx.(*Context); ok {
+		return routeRegistryFromContext(context.instance)
+	}
+
+	return nil
+}
+
+func routeRegistryFromContext(c *gin.Context) *RouteRegistry {
+	if registry, exist := c.Get(routeRegistryKey); exist {
+		if routeRegistry, ok := registry.(*RouteRegistry); ok {
+			return routeRegistry
+		}
+	}
+
+	return nil
+}
+
+// streamContext returns the context that's canceled when the Route shuts down.
+func (r *RouteRegistry) streamContext() context.Context {
+	r.streamsMu.Lock()
+	defer r.streamsMu.Unlock()
+
+	return r.streams
+}
+
+func (r *RouteRegistry) cancelStreams() {
+	r.streamsMu.Lock()
+	defer r.streamsMu.Unlock()
+
+	r.stopStreams()
+}
+
+// resumeStreams lets the streams run again when the Route serves after a shutdown.
+func (r *RouteRegistry) resumeStreams() {
+	r.streamsMu.Lock()
+	defer r.streamsMu.Unlock()
+
+	if r.streams.Err() != nil {
+		r.streams, r.stopStreams = context.WithCancel(context.Background())
+	}
+}
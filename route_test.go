@@ -1,13 +1,17 @@
 package gin
 
 import (
+	"bufio"
+	"context"
 	"crypto/tls"
 	"errors"
+	"fmt"
 	"io"
 	"mime/multipart"
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -17,8 +21,10 @@ import (
 	contractshttp "github.com/goravel/framework/contracts/http"
 	"github.com/goravel/framework/contracts/validation"
 	configmocks "github.com/goravel/framework/mocks/config"
+	mockslog "github.com/goravel/framework/mocks/log"
 	"github.com/spf13/cast"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -36,12 +42,37 @@ func (s *RouteTestSuite) SetupTest() {
 	s.mockConfig = configmocks.NewConfig(s.T())
 	s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
 	s.mockConfig.EXPECT().GetInt("http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.ginx.auto_options", true).Return(true).Once()
 
 	route, err := NewRoute(s.mockConfig, nil)
 	s.Require().Nil(err)
 	s.route = route
+}
 
-	routes = make(map[string]map[string]contractshttp.Info)
+func (s *RouteTestSuite) TestNewServer() {
+	var states []http.ConnState
+	s.mockConfig.EXPECT().GetInt("http.drivers.ginx.header_limit", 4096).Return(8).Once()
+	s.mockConfig.EXPECT().GetInt("http.drivers.ginx.read_header_timeout", 10).Return(5).Once()
+	s.mockConfig.EXPECT().GetInt("http.drivers.ginx.read_timeout", 0).Return(30).Once()
+	s.mockConfig.EXPECT().GetInt("http.drivers.ginx.write_timeout", 0).Return(60).Once()
+	s.mockConfig.EXPECT().GetInt("http.drivers.ginx.idle_timeout", 120).Return(90).Once()
+	s.mockConfig.EXPECT().Get("http.drivers.ginx.conn_state").Return(func(conn net.Conn, state http.ConnState) {
+		states = append(states, state)
+	}).Once()
+	s.mockConfig.EXPECT().GetBool("http.drivers.ginx.keep_alive", true).Return(false).Once()
+
+	server := s.route.newServer("127.0.0.1:3000")
+	s.Equal("127.0.0.1:3000", server.Addr)
+	s.Equal(8<<10, server.MaxHeaderBytes)
+	s.Equal(5*time.Second, server.ReadHeaderTimeout)
+	s.Equal(30*time.Second, server.ReadTimeout)
+	s.Equal(60*time.Second, server.WriteTimeout)
+	s.Equal(90*time.Second, server.IdleTimeout)
+	s.Require().NotNil(server.ConnState)
+
+	server.ConnState(nil, http.StateNew)
+	s.Equal([]http.ConnState{http.StateNew}, states)
 }
 
 func (s *RouteTestSuite) TestRecoverWithCustomCallback() {
@@ -91,6 +122,66 @@ func (s *RouteTestSuite) TestFallback() {
 	s.Equal(http.StatusNotFound, w.Code)
 }
 
+func (s *RouteTestSuite) TestMethodNotAllowed() {
+	handler := func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().String(http.StatusOK, "ok")
+	}
+	s.route.Get("/users", handler)
+	s.route.Post("/users", handler)
+	s.route.Options("/options", handler)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/users", nil)
+	s.route.ServeHTTP(w, req)
+	s.Equal(http.StatusMethodNotAllowed, w.Code)
+	s.Equal("GET, HEAD, POST, OPTIONS", w.Header().Get("Allow"))
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("OPTIONS", "/users", nil)
+	s.route.ServeHTTP(w, req)
+	s.Equal(http.StatusNoContent, w.Code)
+	s.Equal("GET, HEAD, POST, OPTIONS", w.Header().Get("Allow"))
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("OPTIONS", "/options", nil)
+	s.route.ServeHTTP(w, req)
+	s.Equal(http.StatusOK, w.Code)
+	s.Equal("ok", w.Body.String())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", "/options", nil)
+	s.route.ServeHTTP(w, req)
+	s.Equal(http.StatusMethodNotAllowed, w.Code)
+	s.Equal("OPTIONS", w.Header().Get("Allow"))
+}
+
+func (s *RouteTestSuite) TestMethodNotAllowedDisabled() {
+	mockConfig := configmocks.NewConfig(s.T())
+	mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.method_not_allowed", true).Return(false).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.auto_options", true).Return(false).Once()
+
+	route, err := NewRoute(mockConfig, nil)
+	s.Require().Nil(err)
+
+	route.Fallback(func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().String(http.StatusNotFound, "not found")
+	})
+	route.Get("/users", func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().String(http.StatusOK, "ok")
+	})
+
+	for _, method := range []string{"DELETE", "OPTIONS"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, "/users", nil)
+		route.ServeHTTP(w, req)
+		s.Equal(http.StatusNotFound, w.Code)
+		s.Equal("not found", w.Body.String())
+		s.Empty(w.Header().Get("Allow"))
+	}
+}
+
 func (s *RouteTestSuite) TestGetRoutes() {
 	s.route.Get("/b/{id}", func(ctx contractshttp.Context) contractshttp.Response {
 		return ctx.Response().String(200, "ok")
@@ -112,22 +203,186 @@ func (s *RouteTestSuite) TestGetRoutes() {
 	s.Equal("/b/{id}", routes[2].Path)
 }
 
+func (s *RouteTestSuite) TestGetRoutesIsolatedPerRoute() {
+	mockConfig := configmocks.NewConfig(s.T())
+	mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.auto_options", true).Return(true).Once()
+
+	another, err := NewRoute(mockConfig, nil)
+	s.Require().Nil(err)
+
+	s.route.Get("/a", func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().Json(200, contractshttp.Json{
+			"name": ctx.Request().Name(),
+		})
+	}).Name("a")
+	another.Get("/a", func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().Json(200, contractshttp.Json{
+			"name": ctx.Request().Name(),
+		})
+	}).Name("b")
+	another.Post("/b", func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().String(200, "ok")
+	})
+
+	s.Len(s.route.GetRoutes(), 1)
+	s.Len(another.GetRoutes(), 2)
+	s.Equal("a", s.route.Info("a").Name)
+	s.Empty(s.route.Info("b").Name)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/a", nil)
+	another.ServeHTTP(w, req)
+	s.Equal("{\"name\":\"b\"}", w.Body.String())
+}
+
 func (s *RouteTestSuite) TestGlobalMiddleware() {
 	// has timeout middleware
+	s.mockConfig.EXPECT().GetInt("http.drivers.ginx.max_in_flight", 0).Return(0).Once()
 	s.mockConfig.EXPECT().GetInt("http.request_timeout", 3).Return(1).Once()
 	s.route.GlobalMiddleware()
-	s.Len(s.route.instance.Handlers, 5)
+	s.Len(s.route.instance.Handlers, 9)
+	s.True(s.route.registry.requestTimeout.Load())
 
 	// no timeout middleware
 	s.SetupTest()
+	s.mockConfig.EXPECT().GetInt("http.drivers.ginx.max_in_flight", 0).Return(0).Once()
 	s.mockConfig.EXPECT().GetInt("http.request_timeout", 3).Return(0).Once()
 	s.route.GlobalMiddleware()
-	s.Len(s.route.instance.Handlers, 4)
+	s.Len(s.route.instance.Handlers, 8)
+	s.False(s.route.registry.requestTimeout.Load())
+
+	// has concurrency limit middleware
+	s.SetupTest()
+	s.mockConfig.EXPECT().GetInt("http.drivers.ginx.max_in_flight", 0).Return(100).Once()
+	s.mockConfig.EXPECT().GetInt("http.drivers.ginx.queue_timeout", 100).Return(100).Once()
+	s.mockConfig.EXPECT().GetInt("http.request_timeout", 3).Return(1).Once()
+	s.route.GlobalMiddleware()
+	s.Len(s.route.instance.Handlers, 10)
+
+	// the timeout middlewares given to GlobalMiddleware
+	s.SetupTest()
+	s.mockConfig.EXPECT().GetInt("http.drivers.ginx.max_in_flight", 0).Return(0).Once()
+	s.mockConfig.EXPECT().GetInt("http.request_timeout", 3).Return(0).Once()
+	s.route.GlobalMiddleware(Timeout(time.Second))
+	s.Len(s.route.instance.Handlers, 9)
+	s.False(s.route.registry.requestTimeout.Load())
+	s.True(s.route.registry.globalTimeout.Load())
+}
+
+func (s *RouteTestSuite) TestRouteConflict() {
+	handler := func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().Success().String("Goravel")
+	}
+
+	s.Run("fail to run", func() {
+		s.SetupTest()
+
+		s.route.Get("/users/{id}", handler)
+		s.route.Get("/users/{name}", handler)
+
+		s.ErrorContains(s.route.Run("127.0.0.1:3047"), "failed to register the route GET|HEAD /users/{name}: ':name' in new path '/users/:name' conflicts with existing wildcard ':id'")
+		s.False(s.route.Ready())
+	})
+
+	s.Run("the conflict is reported once when serving", func() {
+		s.SetupTest()
+
+		s.route.Get("/users/{id}", handler)
+		s.route.Get("/users/{name}", handler)
+		s.route.Get("/posts", handler)
+
+		mockLog := mockslog.NewLog(s.T())
+		mockLog.EXPECT().Error(mock.MatchedBy(func(err error) bool {
+			return strings.Contains(err.Error(), "failed to register the route GET|HEAD /users/{name}")
+		})).Once()
+		LogFacade = mockLog
+		defer func() {
+			LogFacade = nil
+		}()
+
+		for i := 0; i < 3; i++ {
+			w := httptest.NewRecorder()
+			s.route.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts", nil))
+			s.Equal(http.StatusOK, w.Code)
+		}
+
+		w := httptest.NewRecorder()
+		s.route.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+		s.Equal(http.StatusOK, w.Code)
+	})
+
+	s.Run("add routes while serving", func() {
+		s.SetupTest()
+
+		s.route.Get("/posts", handler)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				w := httptest.NewRecorder()
+				s.route.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts", nil))
+				s.Equal(http.StatusOK, w.Code)
+			}()
+
+			s.route.Get(fmt.Sprintf("/posts/%d", i), handler)
+		}
+		wg.Wait()
+
+		w := httptest.NewRecorder()
+		s.route.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts/99", nil))
+		s.Equal(http.StatusOK, w.Code)
+	})
+
+	s.Run("add routes while a stream is open", func() {
+		s.SetupTest()
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		s.route.Get("/stream", func(ctx contractshttp.Context) contractshttp.Response {
+			close(started)
+			<-release
+
+			return ctx.Response().Success().String("stream")
+		})
+		streamed := make(chan struct{})
+		go func() {
+			defer close(streamed)
+
+			w := httptest.NewRecorder()
+			s.route.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream", nil))
+			s.Equal(http.StatusOK, w.Code)
+		}()
+		<-started
+
+		// the stream doesn't block the registration of the new route, nor the requests behind it
+		s.route.Get("/late", handler)
+		served := make(chan int)
+		go func() {
+			w := httptest.NewRecorder()
+			s.route.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/late", nil))
+			served <- w.Code
+		}()
+		select {
+		case code := <-served:
+			s.Equal(http.StatusOK, code)
+		case <-time.After(time.Second):
+			s.Fail("the request is blocked by the stream")
+		}
+
+		close(release)
+		<-streamed
+	})
 }
 
 func (s *RouteTestSuite) TestListen() {
 	s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
-	s.mockConfig.EXPECT().GetInt("http.drivers.ginx.header_limit", 4096).Return(4096).Once()
+	mockServerConfig(s.mockConfig)
 
 	s.route.Get("/", func(ctx contractshttp.Context) contractshttp.Response {
 		return ctx.Response().Json(200, contractshttp.Json{
@@ -162,7 +417,7 @@ func (s *RouteTestSuite) TestListenTLS() {
 		})
 	})
 
-	s.mockConfig.EXPECT().GetInt("http.drivers.ginx.header_limit", 4096).Return(4096).Once()
+	mockTLSServerConfig(s.mockConfig)
 	s.mockConfig.EXPECT().GetString("http.tls.ssl.cert").Return("test_ca.crt").Once()
 	s.mockConfig.EXPECT().GetString("http.tls.ssl.key").Return("test_ca.key").Once()
 	s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
@@ -198,7 +453,7 @@ func (s *RouteTestSuite) TestListenTLSWithCert() {
 	})
 
 	s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
-	s.mockConfig.EXPECT().GetInt("http.drivers.ginx.header_limit", 4096).Return(4096).Once()
+	mockTLSServerConfig(s.mockConfig)
 
 	go func() {
 		l, err := net.Listen("tcp", "127.0.0.1:3104")
@@ -236,6 +491,32 @@ func (s *RouteTestSuite) TestInfo() {
 	s.Equal("/test", info.Path)
 }
 
+func (s *RouteTestSuite) TestUrl() {
+	handler := func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().String(200, "ok")
+	}
+	s.route.Get("/users", handler).Name("users.index")
+	s.route.Get("/users/{id}/posts/{post}", handler).Name("users.posts.show")
+
+	url, err := s.route.Url("users.index", nil, nil)
+	s.NoError(err)
+	s.Equal("/users", url)
+
+	url, err = s.route.Url("users.posts.show", map[string]any{"id": 1, "post": "a b"}, map[string]any{"page": 2})
+	s.NoError(err)
+	s.Equal("/users/1/posts/a%20b?page=2", url)
+
+	url, err = s.route.Url("users.posts.show", map[string]any{"id": 1, "post": 2, "sort": "desc"}, nil)
+	s.NoError(err)
+	s.Equal("/users/1/posts/2?sort=desc", url)
+
+	_, err = s.route.Url("users.posts.show", map[string]any{"id": 1}, nil)
+	s.EqualError(err, "missing parameters [post] for route users.posts.show")
+
+	_, err = s.route.Url("undefined", nil, nil)
+	s.EqualError(err, "route undefined is not defined")
+}
+
 func (s *RouteTestSuite) TestRun() {
 	s.Run("error when default port is empty", func() {
 		s.SetupTest()
@@ -263,7 +544,7 @@ func (s *RouteTestSuite) TestRun() {
 		s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
 		s.mockConfig.EXPECT().GetString("http.host").Return(host).Once()
 		s.mockConfig.EXPECT().GetString("http.port").Return(port).Once()
-		s.mockConfig.EXPECT().GetInt("http.drivers.ginx.header_limit", 4096).Return(4096).Once()
+		mockServerConfig(s.mockConfig)
 
 		var err error
 
@@ -299,7 +580,7 @@ func (s *RouteTestSuite) TestRun() {
 		})
 
 		s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
-		s.mockConfig.EXPECT().GetInt("http.drivers.ginx.header_limit", 4096).Return(4096).Once()
+		mockServerConfig(s.mockConfig)
 
 		var err error
 
@@ -352,7 +633,7 @@ func (s *RouteTestSuite) TestRunTLS() {
 		addr := "https://" + host + ":" + port
 
 		s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
-		s.mockConfig.EXPECT().GetInt("http.drivers.ginx.header_limit", 4096).Return(4096).Once()
+		mockTLSServerConfig(s.mockConfig)
 		s.mockConfig.EXPECT().GetString("http.tls.host").Return(host).Once()
 		s.mockConfig.EXPECT().GetString("http.tls.port").Return(port).Once()
 		s.mockConfig.EXPECT().GetString("http.tls.ssl.cert").Return("test_ca.crt").Once()
@@ -398,7 +679,7 @@ func (s *RouteTestSuite) TestRunTLS() {
 		port := "3034"
 		addr := "https://" + host + ":" + port
 
-		s.mockConfig.EXPECT().GetInt("http.drivers.ginx.header_limit", 4096).Return(4096).Once()
+		mockTLSServerConfig(s.mockConfig)
 		s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
 		s.mockConfig.EXPECT().GetString("http.tls.ssl.cert").Return("test_ca.crt").Once()
 		s.mockConfig.EXPECT().GetString("http.tls.ssl.key").Return("test_ca.key").Once()
@@ -469,7 +750,7 @@ func (s *RouteTestSuite) TestRunTLSWithCert() {
 			})
 		})
 
-		s.mockConfig.EXPECT().GetInt("http.drivers.ginx.header_limit", 4096).Return(4096).Once()
+		mockTLSServerConfig(s.mockConfig)
 		s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
 
 		var (
@@ -506,6 +787,112 @@ func (s *RouteTestSuite) TestRunTLSWithCert() {
 	})
 }
 
+func (s *RouteTestSuite) TestRunAll() {
+	s.Run("error when port is empty", func() {
+		s.SetupTest()
+
+		s.mockConfig.EXPECT().GetString("http.port").Return("3040").Once()
+		s.mockConfig.EXPECT().GetString("http.tls.port").Return("").Once()
+
+		s.Equal(errors.New("port can't be empty"), s.route.RunAll())
+	})
+
+	s.Run("error when certificate is empty", func() {
+		s.SetupTest()
+
+		s.mockConfig.EXPECT().GetString("http.port").Return("3040").Once()
+		s.mockConfig.EXPECT().GetString("http.tls.port").Return("3041").Once()
+		s.mockConfig.EXPECT().GetString("http.tls.ssl.cert").Return("").Once()
+		s.mockConfig.EXPECT().GetString("http.tls.ssl.key").Return("test_ca.key").Once()
+
+		s.Equal(errors.New("certificate can't be empty"), s.route.RunAll())
+	})
+
+	s.Run("happy path", func() {
+		s.SetupTest()
+
+		s.route.Get("/", func(ctx contractshttp.Context) contractshttp.Response {
+			return ctx.Response().Success().String("Goravel")
+		})
+
+		s.mockConfig.EXPECT().GetString("http.host").Return("127.0.0.1").Once()
+		s.mockConfig.EXPECT().GetString("http.port").Return("3040").Once()
+		s.mockConfig.EXPECT().GetString("http.tls.host").Return("127.0.0.1").Once()
+		s.mockConfig.EXPECT().GetString("http.tls.port").Return("3041").Once()
+		s.mockConfig.EXPECT().GetString("http.tls.ssl.cert").Return("test_ca.crt").Once()
+		s.mockConfig.EXPECT().GetString("http.tls.ssl.key").Return("test_ca.key").Once()
+		s.mockConfig.EXPECT().GetInt("http.drivers.ginx.hsts_max_age", 31536000).Return(3600).Once()
+		s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+		mockServerConfig(s.mockConfig)
+		mockTLSServerConfig(s.mockConfig)
+
+		done := make(chan error)
+		go func() {
+			done <- s.route.RunAll()
+		}()
+
+		time.Sleep(1 * time.Second)
+
+		client := &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+
+		resp, err := client.Get("http://127.0.0.1:3040/path?name=goravel")
+		s.Require().NoError(err)
+		_ = resp.Body.Close()
+		s.Equal(http.StatusMovedPermanently, resp.StatusCode)
+		s.Equal("https://127.0.0.1:3041/path?name=goravel", resp.Header.Get("Location"))
+
+		resp, err = client.Post("http://127.0.0.1:3040/path", "text/plain", nil)
+		s.Require().NoError(err)
+		_ = resp.Body.Close()
+		s.Equal(http.StatusPermanentRedirect, resp.StatusCode)
+
+		resp, err = client.Get("https://127.0.0.1:3041")
+		s.Require().NoError(err)
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		s.NoError(err)
+		s.Equal("Goravel", string(body))
+		s.Equal("max-age=3600", resp.Header.Get("Strict-Transport-Security"))
+
+		s.NoError(s.route.Shutdown())
+		s.NoError(<-done)
+
+		_, err = client.Get("https://127.0.0.1:3041")
+		s.Error(err)
+	})
+
+	s.Run("shut down the other server when one fails", func() {
+		s.SetupTest()
+
+		l, err := net.Listen("tcp", "127.0.0.1:3042")
+		s.Require().NoError(err)
+		defer func() {
+			_ = l.Close()
+		}()
+
+		s.mockConfig.EXPECT().GetString("http.host").Return("127.0.0.1").Once()
+		s.mockConfig.EXPECT().GetString("http.port").Return("3042").Once()
+		s.mockConfig.EXPECT().GetString("http.tls.host").Return("127.0.0.1").Once()
+		s.mockConfig.EXPECT().GetString("http.tls.port").Return("3043").Once()
+		s.mockConfig.EXPECT().GetString("http.tls.ssl.cert").Return("test_ca.crt").Once()
+		s.mockConfig.EXPECT().GetString("http.tls.ssl.key").Return("test_ca.key").Once()
+		s.mockConfig.EXPECT().GetInt("http.drivers.ginx.hsts_max_age", 31536000).Return(0).Once()
+		s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
+		mockServerConfig(s.mockConfig)
+		mockTLSServerConfig(s.mockConfig)
+
+		s.ErrorContains(s.route.RunAll(), "address already in use")
+		s.False(s.route.Ready())
+	})
+}
+
 func (s *RouteTestSuite) TestNewRoute() {
 	defaultTemplate, err := DefaultTemplate()
 	s.Require().Nil(err)
@@ -558,6 +945,8 @@ func (s *RouteTestSuite) TestNewRoute() {
 
 			s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
 			s.mockConfig.EXPECT().GetInt("http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+			s.mockConfig.EXPECT().GetBool("http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+			s.mockConfig.EXPECT().GetBool("http.drivers.ginx.auto_options", true).Return(true).Once()
 			test.setup()
 			route, err := NewRoute(s.mockConfig, test.parameters)
 			s.Equal(test.expectError, err)
@@ -581,7 +970,7 @@ func (s *RouteTestSuite) TestShutdown() {
 		})
 
 		s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
-		s.mockConfig.EXPECT().GetInt("http.drivers.ginx.header_limit", 4096).Return(4096).Once()
+		mockServerConfig(s.mockConfig)
 		s.mockConfig.EXPECT().GetString("http.host").Return(host).Once()
 		s.mockConfig.EXPECT().GetString("http.port").Return(port).Once()
 
@@ -619,7 +1008,7 @@ func (s *RouteTestSuite) TestShutdown() {
 		})
 
 		s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Once()
-		s.mockConfig.EXPECT().GetInt("http.drivers.ginx.header_limit", 4096).Return(4096).Once()
+		mockServerConfig(s.mockConfig)
 		s.mockConfig.EXPECT().GetString("http.host").Return(host).Once()
 		s.mockConfig.EXPECT().GetString("http.port").Return(port).Once()
 
@@ -650,6 +1039,124 @@ func (s *RouteTestSuite) TestShutdown() {
 		wg.Wait()
 		s.Equal(int64(3), count.Load())
 	})
+
+	s.Run("shutdown both servers and cancel the streams", func() {
+		s.SetupTest()
+
+		s.route.Get("/", func(ctx contractshttp.Context) contractshttp.Response {
+			return ctx.Response().Success().String("Goravel")
+		})
+		s.route.Get("/stream", func(ctx contractshttp.Context) contractshttp.Response {
+			return ctx.Response().Stream(http.StatusOK, func(w contractshttp.StreamWriter) error {
+				for {
+					if _, err := w.WriteString("Goravel\n"); err != nil {
+						return err
+					}
+					if err := w.Flush(); err != nil {
+						return err
+					}
+					time.Sleep(10 * time.Millisecond)
+				}
+			})
+		})
+
+		s.mockConfig.EXPECT().GetBool("app.debug").Return(true).Twice()
+		mockServerConfig(s.mockConfig)
+		mockTLSServerConfig(s.mockConfig)
+
+		go func() {
+			s.NoError(s.route.Run(host + ":" + port))
+		}()
+		go func() {
+			l, err := net.Listen("tcp", host+":3037")
+			s.Require().NoError(err)
+			s.NoError(s.route.ListenTLSWithCert(l, "test_ca.crt", "test_ca.key"))
+		}()
+
+		time.Sleep(1 * time.Second)
+
+		s.True(s.route.Ready())
+
+		resp, err := http.Get(addr + "/stream")
+		s.Require().NoError(err)
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+
+		line, err := bufio.NewReader(resp.Body).ReadString('\n')
+		s.NoError(err)
+		s.Equal("Goravel\n", line)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		s.NoError(s.route.Shutdown(ctx))
+		s.False(s.route.Ready())
+		s.Equal(int64(0), s.route.InFlight())
+
+		_, err = io.ReadAll(resp.Body)
+		s.NoError(err)
+
+		assertHttpNormal(s.T(), addr, false)
+		_, err = (&http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}).Get("https://" + host + ":3037")
+		s.Error(err)
+	})
+
+	s.Run("report the in-flight requests when timing out", func() {
+		s.SetupTest()
+
+		unblock := make(chan struct{})
+		s.route.Get("/", func(ctx contractshttp.Context) contractshttp.Response {
+			<-unblock
+			return ctx.Response().Success().String("Goravel")
+		})
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			s.route.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		}()
+
+		s.Eventually(func() bool {
+			return s.route.InFlight() == 1
+		}, time.Second, time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		s.EqualError(s.route.Shutdown(ctx), "1 requests are still in flight: context deadline exceeded")
+
+		close(unblock)
+		<-done
+		s.Equal(int64(0), s.route.InFlight())
+	})
+
+	s.Run("wait for the handlers of the timed out requests", func() {
+		s.SetupTest()
+
+		unblock := make(chan struct{})
+		s.route.Middleware(Timeout(10*time.Millisecond)).Get("/", func(ctx contractshttp.Context) contractshttp.Response {
+			<-unblock
+			return ctx.Response().Success().String("Goravel")
+		})
+
+		w := httptest.NewRecorder()
+		s.route.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		s.Equal(http.StatusRequestTimeout, w.Code)
+		// the response is sent, while the handler is still running
+		s.Equal(int64(1), s.route.InFlight())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		s.EqualError(s.route.Shutdown(ctx), "1 requests are still in flight: context deadline exceeded")
+
+		time.AfterFunc(50*time.Millisecond, func() {
+			close(unblock)
+		})
+		s.NoError(s.route.Shutdown())
+		s.Equal(int64(0), s.route.InFlight())
+	})
 }
 
 func (s *RouteTestSuite) TestTest() {
@@ -690,6 +1197,22 @@ func assertHttpNormal(t *testing.T, addr string, expectNormal bool) {
 	}
 }
 
+func mockServerConfig(mockConfig *configmocks.Config) {
+	mockConfig.EXPECT().GetInt("http.drivers.ginx.header_limit", 4096).Return(4096).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.ginx.read_header_timeout", 10).Return(10).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.ginx.read_timeout", 0).Return(0).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.ginx.write_timeout", 0).Return(0).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.ginx.idle_timeout", 120).Return(120).Once()
+	mockConfig.EXPECT().Get("http.drivers.ginx.conn_state").Return(nil).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.keep_alive", true).Return(true).Once()
+}
+
+func mockTLSServerConfig(mockConfig *configmocks.Config) {
+	mockConfig.EXPECT().GetString("http.drivers.ginx.client_auth").Return("").Once()
+	mockConfig.EXPECT().GetString("http.drivers.ginx.client_ca").Return("").Once()
+	mockServerConfig(mockConfig)
+}
+
 type CreateUser struct {
 	Name string `form:"name" json:"name"`
 }
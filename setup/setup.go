@@ -10,9 +10,35 @@ import (
 )
 
 var config = `map[string]any{
-        // Optional, default is 4096 KB
+        // Optional, default is 4096 KB, the larger request bodies are rejected with 413 Request Entity Too Large
         "body_limit": 4096,
         "header_limit": 4096,
+        // Optional, the timeouts of the server in seconds, 0 means no timeout. The write timeout covers the
+        // whole response, keep it longer than the streaming responses. Default is 10, 0, 0 and 120.
+        "read_header_timeout": 10,
+        "read_timeout": 0,
+        "write_timeout": 0,
+        "idle_timeout": 120,
+        // Optional, disable it to close the connection after each response, default is true
+        "keep_alive": true,
+        // Optional, the max-age of the Strict-Transport-Security header set by route.RunAll in seconds, 0 disables
+        // it, default is 31536000
+        "hsts_max_age": 31536000,
+        // Optional, the client authentication of mutual TLS: none, request, require or verify_if_given, default is
+        // none. The client certificates are verified by the client_ca bundle, it's required by require and
+        // verify_if_given.
+        "client_auth": "none",
+        "client_ca": "",
+        // Optional, called when a connection changes its state
+        // "conn_state": func(conn net.Conn, state nethttp.ConnState) {},
+        // Optional, respond 405 with the Allow header when the method of a request doesn't match, default is true
+        "method_not_allowed": true,
+        // Optional, respond the OPTIONS requests of the routes that don't define them, default is true
+        "auto_options": true,
+        // Optional, the max number of the requests handled at the same time, the requests over it wait up to
+        // queue_timeout milliseconds and then get 503, default is 0 (unlimited)
+        "max_in_flight": 0,
+        "queue_timeout": 100,
         "route": func() (route.Route, error) {
             r := ginxfacades.Route("ginx")
             if r == nil {
@@ -15,10 +15,20 @@ func (w *StreamWriter) Flush() error {
 	return nil
 }
 
+// Write returns the error of the request context once it's done, so the stream stops when the client goes
+// away or the Route shuts down.
 func (w *StreamWriter) Write(data []byte) (int, error) {
+	if err := w.instance.Request.Context().Err(); err != nil {
+		return 0, err
+	}
+
 	return w.instance.Writer.Write(data)
 }
 
 func (w *StreamWriter) WriteString(s string) (int, error) {
+	if err := w.instance.Request.Context().Err(); err != nil {
+		return 0, err
+	}
+
 	return w.instance.Writer.WriteString(s)
 }
@@ -1,15 +1,22 @@
 package gin
 
 import (
+	"errors"
+	"fmt"
 	"html/template"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/gin-gonic/gin/render"
 	"github.com/goravel/framework/support/file"
 	"github.com/goravel/framework/support/path"
+	"github.com/spf13/cast"
 )
 
+// routeTemplates keeps the templates created by NewTemplate whose route function is the placeholder.
+var routeTemplates sync.Map
+
 type Delims struct {
 	Left  string
 	Right string
@@ -25,6 +32,9 @@ func NewTemplate(options RenderOptions) (*render.HTMLProduction, error) {
 	if options.Delims != nil {
 		instance.Delims(options.Delims.Left, options.Delims.Right)
 	}
+	// The route function is bound to the route registry when the template is used by NewRoute.
+	instance.Funcs(template.FuncMap{"route": routeFunc(nil)})
+	_, customRoute := options.FuncMap["route"]
 	if options.FuncMap != nil {
 		instance.Funcs(options.FuncMap)
 	}
@@ -53,6 +63,9 @@ func NewTemplate(options RenderOptions) (*render.HTMLProduction, error) {
 	}
 
 	tmpl := template.Must(instance.ParseFiles(files...))
+	if !customRoute {
+		routeTemplates.Store(tmpl, struct{}{})
+	}
 
 	return &render.HTMLProduction{Template: tmpl}, nil
 }
@@ -61,3 +74,48 @@ func NewTemplate(options RenderOptions) (*render.HTMLProduction, error) {
 func DefaultTemplate() (*render.HTMLProduction, error) {
 	return NewTemplate(RenderOptions{})
 }
+
+// routeFunc creates the "route" template function, it accepts either a map or key/value pairs as parameters:
+// {{ route "users.show" "id" 1 }}.
+func routeFunc(registry *RouteRegistry) func(name string, params ...any) (string, error) {
+	return func(name string, params ...any) (string, error) {
+		if registry == nil {
+			return "", errors.New("route function is not bound to a router")
+		}
+
+		if len(params) == 1 {
+			return registry.Url(name, cast.ToStringMap(params[0]), nil)
+		}
+		if len(params)%2 != 0 {
+			return "", errors.New("route parameters must be key/value pairs")
+		}
+
+		paramsMap := make(map[string]any)
+		for i := 0; i < len(params); i += 2 {
+			paramsMap[cast.ToString(params[i])] = params[i+1]
+		}
+
+		return registry.Url(name, paramsMap, nil)
+	}
+}
+
+// bindRouteFunc binds the route function of a template created by NewTemplate to the registry, the route
+// functions of the other templates are kept. The template may be shared by several Routes, so the function
+// is bound to a clone of it.
+func bindRouteFunc(htmlRender render.HTMLRender, registry *RouteRegistry) (render.HTMLRender, error) {
+	production, ok := htmlRender.(*render.HTMLProduction)
+	if !ok || production == nil || production.Template == nil {
+		return htmlRender, nil
+	}
+	if _, exist := routeTemplates.Load(production.Template); !exist {
+		return htmlRender, nil
+	}
+
+	tmpl, err := production.Template.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind the route function of the template: %w", err)
+	}
+	tmpl.Funcs(template.FuncMap{"route": routeFunc(registry)})
+
+	return &render.HTMLProduction{Template: tmpl, Delims: production.Delims}, nil
+}
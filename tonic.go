@@ -80,7 +80,7 @@ func (t *TonicGroup) addTonicRoute(method, path string, handlers ...contractshtt
 		Handlers: ginHandlers,
 	})
 
-	return NewAction(method, t.Group.getFullPath(path), t.Group.getHandlerName(handlers[len(handlers)-1]))
+	return NewAction(t.Group.registry, method, t.Group.getFullPath(path), t.Group.getHandlerName(handlers[len(handlers)-1]))
 }
 
 // GetTyped adds a typed GET route with Tonic documentation
@@ -122,7 +122,7 @@ func addTonicRouteTyped[Req any, Res any](t *TonicGroup, method, path string, ha
 		Handlers: ginHandlers,
 	})
 
-	return NewAction(method, t.Group.getFullPath(path), t.Group.getHandlerName(handlers[len(handlers)-1]))
+	return NewAction(t.Group.registry, method, t.Group.getFullPath(path), t.Group.getHandlerName(handlers[len(handlers)-1]))
 }
 
 // AddTonicRoute adds a route with Tonic documentation support (legacy method)
@@ -156,7 +156,7 @@ func AddTonicRouteTyped[Req any, Res any](group contractsroute.Router, openapi *
 		Handlers: ginHandlers,
 	})
 
-	return NewAction(method, g.getFullPath(path), g.getHandlerName(handlers[len(handlers)-1]))
+	return NewAction(g.registry, method, g.getFullPath(path), g.getHandlerName(handlers[len(handlers)-1]))
 }
 
 // BindMiddleware creates a middleware that binds and validates request data using Gin's binding
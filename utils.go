@@ -1,16 +1,24 @@
 package gin
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
 	"time"
+	"unsafe"
 
 	"github.com/gin-gonic/gin"
 	"github.com/goravel/framework/contracts/config"
 	httpcontract "github.com/goravel/framework/contracts/http"
+	"github.com/goravel/framework/support/debug"
 )
 
+var funcClosureRegex = regexp.MustCompile(`(\.func\d+|\.\d+)+$`)
+
 func pathToGinPath(relativePath string) string {
 	return bracketToColon(relativePath)
 }
@@ -24,6 +32,38 @@ func middlewaresToGinHandlers(middlewares []httpcontract.Middleware) []gin.Handl
 	return ginHandlers
 }
 
+// containsMiddleware compares the middlewares by identity. reflect.Value.Pointer returns the code of the function,
+// which is shared by every closure of a function literal, e.g. Throttle("login") and Throttle("api"), so the
+// closures themselves are compared instead.
+func containsMiddleware(middlewares []httpcontract.Middleware, middleware httpcontract.Middleware) bool {
+	pointer := middlewarePointer(middleware)
+	for _, item := range middlewares {
+		if middlewarePointer(item) == pointer {
+			return true
+		}
+	}
+
+	return false
+}
+
+// middlewarePointer returns the address of the closure of the middleware, a func value is a pointer to it.
+func middlewarePointer(middleware httpcontract.Middleware) unsafe.Pointer {
+	return *(*unsafe.Pointer)(unsafe.Pointer(&middleware))
+}
+
+// middlewareName returns the name of the function that creates the middleware, e.g. github.com/goravel/gin.Timeout.
+func middlewareName(middleware httpcontract.Middleware) string {
+	return funcClosureRegex.ReplaceAllString(debug.GetFuncInfo(middleware).Name, "")
+}
+
+// middlewareLiteral returns the position of the function of the middleware, the closures of a function literal
+// share it even if they get different pointers.
+func middlewareLiteral(middleware httpcontract.Middleware) string {
+	info := debug.GetFuncInfo(middleware)
+
+	return fmt.Sprintf("%s:%d", info.File, info.Line)
+}
+
 func handlerToGinHandler(handler httpcontract.HandlerFunc) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		context := NewContext(c)
@@ -56,6 +96,47 @@ func middlewareToGinHandler(middleware httpcontract.Middleware) gin.HandlerFunc
 	}
 }
 
+// checkOptionalPath returns an error if an optional parameter of the path isn't trailing, e.g.
+// /posts/{slug?}/comments, it can't be omitted without changing the meaning of the following segments.
+func checkOptionalPath(path string) error {
+	segments := strings.Split(path, "/")
+	trailing := true
+	for i := len(segments) - 1; i >= 0; i-- {
+		optional := strings.HasPrefix(segments[i], "{") && strings.HasSuffix(segments[i], "?}")
+		if optional && !trailing {
+			return fmt.Errorf("the optional parameter %s of the route %s must be trailing", segments[i], path)
+		}
+		if !optional {
+			trailing = false
+		}
+	}
+
+	return nil
+}
+
+// expandOptionalPath expands the trailing optional parameters of a path into the paths need to be registered,
+// e.g. /posts/{year?}/{month?} is expanded to /posts/{year}/{month}, /posts/{year} and /posts.
+func expandOptionalPath(path string) []string {
+	segments := strings.Split(path, "/")
+	required := len(segments)
+	for required > 0 && strings.HasPrefix(segments[required-1], "{") && strings.HasSuffix(segments[required-1], "?}") {
+		required--
+		segments[required] = strings.TrimSuffix(segments[required], "?}") + "}"
+	}
+
+	paths := []string{strings.Join(segments, "/")}
+	for i := len(segments) - 1; i >= required; i-- {
+		expanded := strings.Join(segments[:i], "/")
+		if expanded == "" {
+			expanded = "/"
+		}
+
+		paths = append(paths, expanded)
+	}
+
+	return paths
+}
+
 func getDebugLog(config config.Config) gin.HandlerFunc {
 	logFormatter := func(param gin.LogFormatterParams) string {
 		var statusColor, methodColor, resetColor string
@@ -110,3 +191,45 @@ func bracketToColon(relativePath string) string {
 
 	return relativePath
 }
+
+// urlSignature calculates the HMAC-SHA256 signature of a url with the app key, see signaturePayload.
+func urlSignature(key, url string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(url))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// signaturePayload returns the part of a url covered by its signature, it's the path and the query, prefixed by
+// the host for the domain routes, e.g. //tenant.example.com/users?expires=1700000000.
+func signaturePayload(host, url string) string {
+	if host == "" {
+		return url
+	}
+
+	return "//" + strings.ToLower(host) + url
+}
+
+// fillRouteParameters replaces the {key} placeholders of the path with the route parameters.
+func fillRouteParameters(path string, param func(key string) string) string {
+	return routeParameterRegex.ReplaceAllStringFunc(path, func(placeholder string) string {
+		return url.PathEscape(param(strings.TrimSuffix(placeholder[1:len(placeholder)-1], "?")))
+	})
+}
+
+// singular returns the singular form of a plural resource name, e.g. users -> user, categories -> category.
+func singular(word string) string {
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 3:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "sses"), strings.HasSuffix(word, "xes"), strings.HasSuffix(word, "zes"),
+		strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "ss"):
+		return word
+	case strings.HasSuffix(word, "s"):
+		return word[:len(word)-1]
+	}
+
+	return word
+}
@@ -13,3 +13,26 @@ func TestBracketToColon(t *testing.T) {
 func TestColonToBracket(t *testing.T) {
 	assert.Equal(t, "/{id}/{name}", colonToBracket("/:id/:name"))
 }
+
+func TestExpandOptionalPath(t *testing.T) {
+	assert.Equal(t, []string{"/posts/{id}"}, expandOptionalPath("/posts/{id}"))
+	assert.Equal(t, []string{"/posts/{slug}", "/posts"}, expandOptionalPath("/posts/{slug?}"))
+	assert.Equal(t, []string{"/posts/{year}/{month}", "/posts/{year}", "/posts"}, expandOptionalPath("/posts/{year?}/{month?}"))
+	assert.Equal(t, []string{"/{page}", "/"}, expandOptionalPath("/{page?}"))
+}
+
+func TestCheckOptionalPath(t *testing.T) {
+	assert.NoError(t, checkOptionalPath("/posts/{id}"))
+	assert.NoError(t, checkOptionalPath("/posts/{year?}/{month?}"))
+	assert.EqualError(t, checkOptionalPath("/posts/{slug?}/comments"), "the optional parameter {slug?} of the route /posts/{slug?}/comments must be trailing")
+	assert.EqualError(t, checkOptionalPath("/posts/{year?}/{month}"), "the optional parameter {year?} of the route /posts/{year?}/{month} must be trailing")
+}
+
+func TestSingular(t *testing.T) {
+	assert.Equal(t, "user", singular("users"))
+	assert.Equal(t, "category", singular("categories"))
+	assert.Equal(t, "box", singular("boxes"))
+	assert.Equal(t, "address", singular("addresses"))
+	assert.Equal(t, "class", singular("class"))
+	assert.Equal(t, "staff", singular("staff"))
+}
@@ -1,10 +1,13 @@
 package gin
 
 import (
+	htmltemplate "html/template"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/gin-gonic/gin/render"
 	contractshttp "github.com/goravel/framework/contracts/http"
 	foundationjson "github.com/goravel/framework/foundation/json"
 	configmocks "github.com/goravel/framework/mocks/config"
@@ -13,6 +16,7 @@ import (
 	"github.com/goravel/framework/support/file"
 	"github.com/goravel/framework/support/path"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestView_Make(t *testing.T) {
@@ -38,6 +42,8 @@ func TestView_Make(t *testing.T) {
 		mockConfig = &configmocks.Config{}
 		mockConfig.On("GetBool", "app.debug").Return(false).Once()
 		mockConfig.On("GetInt", "http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+		mockConfig.On("GetBool", "http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+		mockConfig.On("GetBool", "http.drivers.ginx.auto_options", true).Return(true).Once()
 		ConfigFacade = mockConfig
 
 		mockView = &httpmocks.View{}
@@ -258,6 +264,8 @@ func TestView_First(t *testing.T) {
 		mockConfig = &configmocks.Config{}
 		mockConfig.On("GetBool", "app.debug").Return(false).Once()
 		mockConfig.On("GetInt", "http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+		mockConfig.On("GetBool", "http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+		mockConfig.On("GetBool", "http.drivers.ginx.auto_options", true).Return(true).Once()
 		ConfigFacade = mockConfig
 
 		mockView = &httpmocks.View{}
@@ -388,6 +396,8 @@ csrf_token={{ .csrf_token }}
 	mockConfig := configmocks.NewConfig(t)
 	mockConfig.EXPECT().GetBool("app.debug").Return(false).Once()
 	mockConfig.EXPECT().GetInt("http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.auto_options", true).Return(true).Once()
 	ConfigFacade = mockConfig
 
 	mockView := httpmocks.NewView(t)
@@ -414,6 +424,134 @@ csrf_token={{ .csrf_token }}
 	assert.Nil(t, file.Remove("resources"))
 }
 
+func TestView_RouteFunc(t *testing.T) {
+	assert.Nil(t, file.PutContent(path.Resource("views", "route.tmpl"), `{{ define "route.tmpl" }}
+{{ route "users.show" "id" 1 }}
+{{ route "users.show" .Params }}
+{{ end }}
+`))
+	mockConfig := configmocks.NewConfig(t)
+	mockConfig.EXPECT().GetBool("app.debug").Return(false).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.auto_options", true).Return(true).Once()
+	ConfigFacade = mockConfig
+
+	mockView := httpmocks.NewView(t)
+	ViewFacade = mockView
+	mockView.EXPECT().GetShared().Return(map[string]any{}).Once()
+
+	route, err := NewRoute(mockConfig, nil)
+	assert.Nil(t, err)
+	route.Get("/users/{id}", func(ctx contractshttp.Context) contractshttp.Response {
+		return ctx.Response().View().Make("route.tmpl", map[string]any{
+			"Params": map[string]any{"id": 2, "tab": "posts"},
+		})
+	}).Name("users.show")
+
+	req, err := http.NewRequest("GET", "/users/1", nil)
+	assert.Nil(t, err)
+	w := httptest.NewRecorder()
+	route.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "\n/users/1\n/users/2?tab=posts\n", w.Body.String())
+
+	assert.Nil(t, file.Remove("resources"))
+}
+
+func TestView_RouteFuncTemplate(t *testing.T) {
+	assert.Nil(t, file.PutContent(path.Resource("views", "route.tmpl"), `{{ define "route.tmpl" }}{{ route "users.show" "id" 1 }}{{ end }}`))
+	defer func() {
+		assert.Nil(t, file.Remove("resources"))
+	}()
+
+	mockView := httpmocks.NewView(t)
+	ViewFacade = mockView
+	mockView.EXPECT().GetShared().Return(map[string]any{})
+
+	newRoute := func(template render.HTMLRender, path string) *Route {
+		mockConfig := configmocks.NewConfig(t)
+		mockConfig.EXPECT().GetBool("app.debug").Return(false).Once()
+		mockConfig.EXPECT().GetInt("http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+		mockConfig.EXPECT().GetBool("http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+		mockConfig.EXPECT().GetBool("http.drivers.ginx.auto_options", true).Return(true).Once()
+		mockConfig.EXPECT().Get("http.drivers.ginx.template").Return(template).Once()
+
+		route, err := NewRoute(mockConfig, map[string]any{"driver": "ginx"})
+		require.NoError(t, err)
+		route.Get(path, func(ctx contractshttp.Context) contractshttp.Response {
+			return ctx.Response().View().Make("route.tmpl")
+		}).Name("users.show")
+
+		return route
+	}
+	body := func(route *Route, url string) string {
+		w := httptest.NewRecorder()
+		route.ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+
+		return w.Body.String()
+	}
+
+	t.Run("the custom route function is kept", func(t *testing.T) {
+		template, err := NewTemplate(RenderOptions{FuncMap: htmltemplate.FuncMap{
+			"route": func(name string, params ...any) string {
+				return "custom " + name
+			},
+		}})
+		require.NoError(t, err)
+
+		assert.Equal(t, "custom users.show", body(newRoute(template, "/users/{id}"), "/users/1"))
+	})
+
+	t.Run("the shared template is bound to each route", func(t *testing.T) {
+		template, err := NewTemplate(RenderOptions{})
+		require.NoError(t, err)
+
+		users := newRoute(template, "/users/{id}")
+		members := newRoute(template, "/members/{id}")
+
+		assert.Equal(t, "/users/1", body(users, "/users/1"))
+		assert.Equal(t, "/members/1", body(members, "/members/1"))
+		assert.ErrorContains(t, template.Template.ExecuteTemplate(io.Discard, "route.tmpl", nil), "route function is not bound to a router")
+	})
+}
+
+func TestView_Route(t *testing.T) {
+	assert.Nil(t, file.PutContent(path.Resource("views", "welcome.tmpl"), `{{ define "welcome.tmpl" }}
+<h1>Hello {{ .Name }}</h1>
+{{ end }}
+`))
+	mockConfig := configmocks.NewConfig(t)
+	mockConfig.EXPECT().GetBool("app.debug").Return(false).Once()
+	mockConfig.EXPECT().GetInt("http.drivers.ginx.body_limit", 4096).Return(4096).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.method_not_allowed", true).Return(true).Once()
+	mockConfig.EXPECT().GetBool("http.drivers.ginx.auto_options", true).Return(true).Once()
+	ConfigFacade = mockConfig
+
+	mockView := httpmocks.NewView(t)
+	ViewFacade = mockView
+	mockView.EXPECT().GetShared().Return(map[string]any{}).Once()
+
+	route, err := NewRoute(mockConfig, nil)
+	assert.Nil(t, err)
+	route.View("/welcome", "welcome.tmpl", map[string]any{"Name": "Goravel"}).Name("welcome")
+
+	req, err := http.NewRequest("GET", "/welcome", nil)
+	assert.Nil(t, err)
+	w := httptest.NewRecorder()
+	route.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "\n<h1>Hello Goravel</h1>\n", w.Body.String())
+	assert.Equal(t, contractshttp.Info{
+		Handler: "View welcome.tmpl",
+		Method:  "GET|HEAD",
+		Path:    "/welcome",
+		Name:    "welcome",
+	}, route.Info("welcome"))
+
+	assert.Nil(t, file.Remove("resources"))
+}
+
 func TestStructToMap(t *testing.T) {
 	data := struct {
 		Name string